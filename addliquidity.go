@@ -0,0 +1,97 @@
+package bscexorcist
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols"
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AddLiquiditySandwichError is returned when a pool's price is moved right before a victim
+// deposits liquidity and moved back right after, so the victim deposits at a skewed ratio.
+// Transactions are identified by their index in the bundle.
+type AddLiquiditySandwichError struct {
+	Pool common.Address
+	// Provider is the victim depositor, the NFT holder for positions minted through a
+	// position manager.
+	Provider common.Address
+	FrontTx  int
+	VictimTx int
+	BackTx   int
+	// ValueLost is the depositor's loss in token1 once the price is restored,
+	// a0·P + a1 - 2·sqrt(a0·a1·P) for deposited amounts a0, a1 and the spot price P
+	// (token1 per token0) before the front-run. It treats the deposit as full-range
+	// liquidity and is nil unless P is known from V2 reserves and the amounts are reported.
+	ValueLost *big.Float
+	// Score weighs the evidence that the pattern is an attack.
+	Score *Score
+}
+
+// Error implements the error interface.
+func (e *AddLiquiditySandwichError) Error() string {
+	msg := fmt.Sprintf("add-liquidity sandwich detected on pool: %s, victim provider %s", e.Pool.Hex(), e.Provider.Hex())
+	if e.ValueLost != nil {
+		msg += fmt.Sprintf(" (value lost: %s token1)", e.ValueLost.Text('g', 6))
+	}
+	if e.Score != nil {
+		msg += fmt.Sprintf("; verdict %s (score %g)", e.Score.Verdict, e.Score.Total)
+	}
+	return msg
+}
+
+// findAddLiquiditySandwich returns the first deposit that lies between two opposite swaps
+// of other transactions on the same pool sharing a trader, see sharedIdentity.
+func findAddLiquiditySandwich(bundleLogs [][]*types.Log, pools []common.Address, poolLegs map[common.Address][]poolLeg, liquidityLegs map[common.Address][]liquidityLeg, reserves *uniswapv2.PoolState) *AddLiquiditySandwichError {
+	for _, pool := range pools {
+		swaps := poolLegs[pool]
+		for _, deposit := range liquidityLegs[pool] {
+			if !deposit.event.IsAdd() {
+				continue
+			}
+			for _, front := range swaps {
+				if front.txIndex >= deposit.txIndex {
+					break
+				}
+				for _, back := range swaps {
+					if back.txIndex <= deposit.txIndex || back.swap.IsToken0To1() == front.swap.IsToken0To1() ||
+						!sharedIdentity(bundleLogs, pools, front.txIndex, back.txIndex, deposit.txIndex) {
+						continue
+					}
+					return &AddLiquiditySandwichError{
+						Pool:      pool,
+						Provider:  protocols.LiquidityOwner(deposit.event),
+						FrontTx:   front.txIndex,
+						VictimTx:  deposit.txIndex,
+						BackTx:    back.txIndex,
+						ValueLost: depositValueLost(reserves, pool, front.txIndex, deposit),
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// depositValueLost computes AddLiquiditySandwichError.ValueLost. Returns nil if the spot
+// price before the front-run or the deposited amounts are unknown.
+func depositValueLost(reserves *uniswapv2.PoolState, pool common.Address, frontTx int, deposit liquidityLeg) *big.Float {
+	snapshots := reserves.TxSnapshots(pool, frontTx)
+	amount0, amount1 := deposit.event.Amount0(), deposit.event.Amount1()
+	if len(snapshots) == 0 || amount0 == nil || amount1 == nil {
+		return nil
+	}
+	price := snapshots[0].SpotPriceBefore()
+	if price == nil {
+		return nil
+	}
+
+	a0, a1 := new(big.Float).SetInt(amount0), new(big.Float).SetInt(amount1)
+	deposited := new(big.Float).Mul(a0, price)
+	deposited.Add(deposited, a1)
+	withdrawable := new(big.Float).Mul(a0, a1)
+	withdrawable.Mul(withdrawable, price).Sqrt(withdrawable).Mul(withdrawable, big.NewFloat(2))
+	return deposited.Sub(deposited, withdrawable)
+}
@@ -0,0 +1,133 @@
+package bscexorcist
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockUnit is a bundle or a loose transaction of a candidate block.
+type BlockUnit struct {
+	// BundleID identifies the bundle, empty for a loose mempool transaction.
+	BundleID string
+	// Logs holds the logs of each transaction of the unit, in order.
+	Logs [][]*types.Log
+}
+
+// IsBundle returns true if the unit is a bundle rather than a loose transaction.
+func (u BlockUnit) IsBundle() bool {
+	return u.BundleID != ""
+}
+
+// TxRef locates a transaction of a candidate block: the index of its unit and its index within the unit.
+type TxRef struct {
+	Unit int
+	Tx   int
+}
+
+// BlockSandwich is a sandwich whose front-run and back-run are in other units than its victims.
+type BlockSandwich struct {
+	Pool    common.Address
+	Front   TxRef
+	Back    TxRef
+	Victims []TxRef
+}
+
+// BlockReport is the outcome of analyzing a candidate block with BlockAnalyzer.Analyze.
+type BlockReport struct {
+	Sandwiches []*BlockSandwich
+	// Exclude lists the indices of the bundles holding front-runs or back-runs, in block order.
+	// Dropping them from the block breaks every sandwich found.
+	Exclude []int
+}
+
+// BlockAnalyzer finds sandwiches assembled across the bundles and loose transactions of a
+// candidate block, which per-bundle detection cannot see.
+type BlockAnalyzer struct {
+	detector *Detector
+}
+
+// NewBlockAnalyzer creates a BlockAnalyzer parsing transactions with the given options.
+func NewBlockAnalyzer(opts Options) *BlockAnalyzer {
+	return &BlockAnalyzer{detector: NewDetector(opts)}
+}
+
+// Analyze analyzes the units of a candidate block in order. Sandwiches entirely within one
+// unit are left to DetectSandwichForBundle; a sandwich is reported here when its victims are
+// in other units than its front-run and back-run, the front-run or back-run is in a bundle,
+// and an account traded in both of them, see sharedIdentity.
+func (a *BlockAnalyzer) Analyze(units []BlockUnit) *BlockReport {
+	var (
+		refs    []TxRef
+		txsLogs [][]*types.Log
+	)
+	index, scratch := newLegIndex(), &Report{}
+	for unitIndex, unit := range units {
+		for txIndex, txLogs := range unit.Logs {
+			a.detector.indexTx(index, scratch, len(refs), txLogs)
+			refs = append(refs, TxRef{Unit: unitIndex, Tx: txIndex})
+			txsLogs = append(txsLogs, txLogs)
+		}
+	}
+
+	blockReport := &BlockReport{}
+	excluded := make(map[int]bool)
+	for _, pool := range index.pools {
+		for _, sandwich := range findBlockSandwiches(txsLogs, index.pools, pool, index.poolLegs[pool], refs) {
+			// Loose transactions trading around each other are ordinary mempool flow.
+			if !units[sandwich.Front.Unit].IsBundle() && !units[sandwich.Back.Unit].IsBundle() {
+				continue
+			}
+			blockReport.Sandwiches = append(blockReport.Sandwiches, sandwich)
+			for _, unit := range []int{sandwich.Front.Unit, sandwich.Back.Unit} {
+				if units[unit].IsBundle() {
+					excluded[unit] = true
+				}
+			}
+		}
+	}
+	for unit := range units {
+		if excluded[unit] {
+			blockReport.Exclude = append(blockReport.Exclude, unit)
+		}
+	}
+	return blockReport
+}
+
+// findBlockSandwiches pairs each leg of a pool with the first later leg in the opposite
+// direction sharing a trader with it, and reports the pair if legs in the first leg's
+// direction from other units lie in between. Pairs of the same front-run and back-run units
+// are reported once. Transactions are indexed in block order in txsLogs and refs.
+func findBlockSandwiches(txsLogs [][]*types.Log, pools []common.Address, pool common.Address, legs []poolLeg, refs []TxRef) []*BlockSandwich {
+	var sandwiches []*BlockSandwich
+	reported := make(map[[2]int]bool)
+	for i, front := range legs {
+		frontRef := refs[front.txIndex]
+		for k := i + 1; k < len(legs); k++ {
+			back := legs[k]
+			if back.swap.IsToken0To1() == front.swap.IsToken0To1() {
+				continue
+			}
+
+			backRef := refs[back.txIndex]
+			var victims []TxRef
+			var victimTxs []int
+			for _, victim := range legs[i+1 : k] {
+				victimRef := refs[victim.txIndex]
+				if victim.swap.IsToken0To1() == front.swap.IsToken0To1() && victimRef.Unit != frontRef.Unit && victimRef.Unit != backRef.Unit {
+					victims = append(victims, victimRef)
+					victimTxs = append(victimTxs, victim.txIndex)
+				}
+			}
+			if !sharedIdentity(txsLogs, pools, front.txIndex, back.txIndex, victimTxs...) {
+				continue
+			}
+			key := [2]int{frontRef.Unit, backRef.Unit}
+			if len(victims) > 0 && !reported[key] {
+				reported[key] = true
+				sandwiches = append(sandwiches, &BlockSandwich{Pool: pool, Front: frontRef, Back: backRef, Victims: victims})
+			}
+			break
+		}
+	}
+	return sandwiches
+}
@@ -0,0 +1,151 @@
+package bscexorcist
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// poolSequence summarizes the directions of a sequence of legs on one pool, enough to tell
+// whether the sequence, or its concatenation with another one, forms a sandwich pattern.
+type poolSequence struct {
+	count   [2]uint8 // count holds the legs per direction (0: token0 -> token1), capped at 2
+	follows [2]bool  // follows[x] is true if a leg in direction x is followed by one in the opposite direction
+	pattern bool     // pattern is true if the sequence contains a Buy-Buy-Sell or Sell-Sell-Buy pattern
+}
+
+// then returns the summary of s followed by next.
+func (s poolSequence) then(next poolSequence) poolSequence {
+	combined := poolSequence{pattern: s.pattern || next.pattern}
+	for x := 0; x < 2; x++ {
+		opposite := 1 - x
+		// Two legs of s before an opposite leg of next, or one leg of s before a leg of next
+		// that is itself followed by an opposite leg.
+		if (s.count[x] >= 2 && next.count[opposite] >= 1) || (s.count[x] >= 1 && next.follows[x]) {
+			combined.pattern = true
+		}
+		combined.follows[x] = s.follows[x] || next.follows[x] || (s.count[x] >= 1 && next.count[opposite] >= 1)
+		combined.count[x] = min(s.count[x]+next.count[x], 2)
+	}
+	return combined
+}
+
+func directionIndex(zeroForOne bool) int {
+	if zeroForOne {
+		return 0
+	}
+	return 1
+}
+
+// BundleSummary is a compact, composable summary of the swap legs of a bundle, per pool.
+// Summaries of bundles can be concatenated with Then to check any merge order without
+// parsing the bundles again.
+type BundleSummary struct {
+	pools map[common.Address]poolSequence
+}
+
+// Summarize parses a bundle according to the detector options and summarizes its swap legs.
+func (d *Detector) Summarize(bundleLogs [][]*types.Log) *BundleSummary {
+	summary := &BundleSummary{pools: make(map[common.Address]poolSequence)}
+	for _, txLogs := range bundleLogs {
+		swaps, _, _ := d.txSwaps(txLogs)
+		for _, swap := range effectiveLegs(swaps) {
+			var leg poolSequence
+			leg.count[directionIndex(swap.IsToken0To1())] = 1
+			summary.pools[swap.PairID()] = summary.pools[swap.PairID()].then(leg)
+		}
+	}
+	return summary
+}
+
+// Then returns the summary of the bundle followed by next.
+func (s *BundleSummary) Then(next *BundleSummary) *BundleSummary {
+	combined := &BundleSummary{pools: make(map[common.Address]poolSequence, len(s.pools)+len(next.pools))}
+	for pool, sequence := range s.pools {
+		combined.pools[pool] = sequence.then(next.pools[pool])
+	}
+	for pool, sequence := range next.pools {
+		if _, ok := s.pools[pool]; !ok {
+			combined.pools[pool] = sequence
+		}
+	}
+	return combined
+}
+
+// SandwichedPools returns the pools whose legs form a sandwich pattern, sorted by address.
+func (s *BundleSummary) SandwichedPools() []common.Address {
+	var pools []common.Address
+	for pool, sequence := range s.pools {
+		if sequence.pattern {
+			pools = append(pools, pool)
+		}
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		return bytes.Compare(pools[i].Bytes(), pools[j].Bytes()) < 0
+	})
+	return pools
+}
+
+// Conflict is an ordered pair of bundles that form a sandwich when the first is placed
+// before the second, although neither does on its own.
+type Conflict struct {
+	First  int
+	Second int
+	// Pools are the pools on which the merged bundles form a sandwich pattern.
+	Pools []common.Address
+}
+
+// ConflictGraph records the conflicts between the bundles given to Detector.ConflictGraph.
+//
+// Conflicts are raw direction patterns over the netted legs of the merged bundles. They are
+// not scored, so shared identities, price evidence and profit do not count, and neither the
+// backrun exemption nor the liquidity patterns of Analyze apply: Analyze the merged bundle
+// to get a verdict.
+type ConflictGraph struct {
+	// Summaries holds the summary of each bundle, to check longer merge orders with Then.
+	Summaries []*BundleSummary
+	// Conflicts lists the conflicting ordered pairs, by first and then second bundle index.
+	Conflicts []Conflict
+}
+
+// Conflicting returns the pools on which placing bundle first before bundle second forms
+// a sandwich, or nil if they do not conflict in that order.
+func (g *ConflictGraph) Conflicting(first, second int) []common.Address {
+	for _, conflict := range g.Conflicts {
+		if conflict.First == first && conflict.Second == second {
+			return conflict.Pools
+		}
+	}
+	return nil
+}
+
+// ConflictGraph summarizes each bundle once and composes the summaries of every ordered
+// pair. Pools on which either bundle already forms a pattern on its own are not conflicts.
+// Sandwiches needing three or more bundles are found by composing Summaries in the
+// candidate order.
+func (d *Detector) ConflictGraph(bundles [][][]*types.Log) *ConflictGraph {
+	graph := &ConflictGraph{}
+	for _, bundleLogs := range bundles {
+		graph.Summaries = append(graph.Summaries, d.Summarize(bundleLogs))
+	}
+
+	for i, first := range graph.Summaries {
+		for j, second := range graph.Summaries {
+			if i == j {
+				continue
+			}
+			var pools []common.Address
+			for _, pool := range first.Then(second).SandwichedPools() {
+				if !first.pools[pool].pattern && !second.pools[pool].pattern {
+					pools = append(pools, pool)
+				}
+			}
+			if len(pools) > 0 {
+				graph.Conflicts = append(graph.Conflicts, Conflict{First: i, Second: j, Pools: pools})
+			}
+		}
+	}
+	return graph
+}
@@ -2,41 +2,348 @@
 package bscexorcist
 
 import (
-	"fmt"
 	"github.com/48Club/bscexorcist/protocols"
+	"github.com/48Club/bscexorcist/protocols/bondingcurve"
+	"github.com/48Club/bscexorcist/protocols/poolverify"
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// Options configures a Detector.
+type Options struct {
+	// TransferFallback infers swaps from ERC20 Transfer events for contracts whose swap
+	// events are not recognized. Detections that rely on inferred swaps are marked heuristic.
+	TransferFallback bool
+
+	// PoolVerifier, if set, checks that emitters of V2/V3 swap events are pools of a
+	// known factory. Swaps from emitters that moved no tokens like a pool are ignored and
+	// listed in Report.SpoofedPools; other emitters are listed in Report.UnverifiedPools.
+	PoolVerifier *poolverify.Verifier
+
+	// KeepUnverifiedPools keeps the swaps of unverified pools in detection. By default
+	// they are ignored like those of spoofed pools, as a contract can fake the transfers
+	// of a pool; pools of forks missing from the factory table can be allow-listed with
+	// Verifier.Allow instead.
+	KeepUnverifiedPools bool
+
+	// V4Pools, if set, records the pools initialized by its PoolManagers and resolves the
+	// tokens of V4 swaps when classifying transactions. It may be shared between detectors
+	// and seeded with PoolRegistry.LoadJSON.
+	V4Pools *uniswapv4.PoolRegistry
+
+	// HookAdapters decode the events of custom-curve V4 hooks that settle swaps without
+	// the standard HookSwap event, keyed by hook contract.
+	HookAdapters uniswapv4.HookAdapters
+
+	// Launchpads, if set, replaces bondingcurve.DefaultLaunchpads as the set of
+	// bonding-curve launchpad events recognized.
+	Launchpads *bondingcurve.Registry
+
+	// WrappedNative is the wrapped native token that native BNB is counted as when
+	// comparing tokens and parsing wraps. The zero address uses WBNB (tools.WBNB); set it
+	// when running against another chain.
+	WrappedNative common.Address
+
+	// KnownBots are addresses of known sandwich bots; a front-run or back-run that
+	// transfers tokens to or from one of them scores higher.
+	KnownBots []common.Address
+
+	// SuspiciousScore and SandwichScore are the Score thresholds of the Suspicious and
+	// Sandwich verdicts. Zero values use DefaultSuspiciousScore and DefaultSandwichScore.
+	SuspiciousScore float64
+	SandwichScore   float64
+}
+
+// Detector detects sandwich attacks in transaction bundles.
+type Detector struct {
+	opts   Options
+	config protocols.Config
+}
+
+// NewDetector creates a Detector with the given options.
+func NewDetector(opts Options) *Detector {
+	return &Detector{
+		opts: opts,
+		config: protocols.Config{
+			HookAdapters:  opts.HookAdapters,
+			Launchpads:    opts.Launchpads,
+			WrappedNative: opts.WrappedNative,
+		},
+	}
+}
+
+// defaultDetector backs the package-level DetectSandwichForBundle.
+var defaultDetector = NewDetector(Options{})
+
+// poolLeg is a swap on a pool together with the index of the transaction it belongs to.
+type poolLeg struct {
+	txIndex int
+	swap    protocols.SwapEvent
+}
+
 // DetectSandwichForBundle analyzes a bundle of transaction logs to identify potential sandwich attacks.
-// Returns an error if a sandwich pattern is detected in any pool within the bundle.
+// Returns an error if a sandwich pattern or a liquidity attack is detected in any pool within the bundle.
 func DetectSandwichForBundle(bundleLogs [][]*types.Log) error {
+	return defaultDetector.DetectSandwichForBundle(bundleLogs)
+}
+
+// DetectSandwichForBundle analyzes a bundle of transaction logs to identify potential sandwich attacks.
+// Returns a *SandwichError if a sandwich pattern is detected in any pool within the bundle,
+// a *JITLiquidityError if liquidity was added and removed around a victim swap, a
+// *LiquidityRemovalError if liquidity was removed and re-added around it, or an
+// *AddLiquiditySandwichError if the price was moved around a victim's liquidity deposit.
+func (d *Detector) DetectSandwichForBundle(bundleLogs [][]*types.Log) error {
+	return d.Analyze(bundleLogs).Err()
+}
+
+// Analyze analyzes a bundle of transaction logs and reports the sandwich detected, if any,
+// together with the evidence gathered along the way.
+func (d *Detector) Analyze(bundleLogs [][]*types.Log) *Report {
+	report := &Report{}
 	if len(bundleLogs) < 3 {
-		return nil
+		return report
+	}
+
+	index := newLegIndex()
+	for txIndex, txLogs := range bundleLogs {
+		d.indexTx(index, report, txIndex, txLogs)
+	}
+	pools, poolLegs, liquidityLegs, reserves := index.pools, index.poolLegs, index.liquidityLegs, index.reserves
+
+	// A user transaction followed only by arbitrages is a backrun bundle, whose swaps are
+	// clean by design. Its liquidity changes are still checked.
+	if !isBackrunBundle(report.Classes) {
+		report.Sandwich = d.findSandwich(bundleLogs, pools, poolLegs, reserves)
+	}
+	report.JIT = findJIT(pools, poolLegs, liquidityLegs)
+	report.LiquidityRemoval = findLiquidityRemoval(pools, poolLegs, liquidityLegs, reserves)
+	report.AddLiquidity = findAddLiquiditySandwich(bundleLogs, pools, poolLegs, liquidityLegs, reserves)
+
+	if report.Sandwich != nil {
+		report.Sandwich.Score = d.scoreSandwich(bundleLogs, report.Sandwich, poolLegs[report.Sandwich.Pool], pools)
+		report.raiseVerdict(report.Sandwich.Score)
+	}
+	if report.JIT != nil {
+		samePosition := withdrewPosition(liquidityLegs[report.JIT.Pool], report.JIT.BurnTx)
+		report.JIT.Score = d.scoreLiquidity(bundleLogs, pools, report.JIT.MintTx, report.JIT.VictimTx, report.JIT.BurnTx, samePosition)
+		report.raiseVerdict(report.JIT.Score)
+	}
+	if removal := report.LiquidityRemoval; removal != nil {
+		samePosition := withdrewPosition(liquidityLegs[removal.Pool], removal.RemoveTx)
+		removal.Score = d.scoreLiquidity(bundleLogs, pools, removal.RemoveTx, removal.VictimTx, removal.AddTx, samePosition)
+		report.raiseVerdict(removal.Score)
+	}
+	if add := report.AddLiquidity; add != nil {
+		add.Score = d.scoreLiquidity(bundleLogs, pools, add.FrontTx, add.VictimTx, add.BackTx, false)
+		report.raiseVerdict(add.Score)
+	}
+	return report
+}
+
+// legIndex holds the legs of a sequence of transactions, grouped by pool.
+type legIndex struct {
+	pools         []common.Address // pools in order of their first swap
+	poolLegs      map[common.Address][]poolLeg
+	liquidityLegs map[common.Address][]liquidityLeg
+	reserves      *uniswapv2.PoolState
+}
+
+func newLegIndex() *legIndex {
+	return &legIndex{
+		poolLegs:      make(map[common.Address][]poolLeg),
+		liquidityLegs: make(map[common.Address][]liquidityLeg),
+		reserves:      uniswapv2.NewPoolState(),
+	}
+}
+
+// indexTx parses the logs of one transaction according to the detector options and adds
+// its legs to the index. Flagged pools and the transaction class are recorded in report.
+func (d *Detector) indexTx(index *legIndex, report *Report, txIndex int, txLogs []*types.Log) {
+	protocols.ObserveReserves(index.reserves, txIndex, txLogs)
+	if d.opts.V4Pools != nil {
+		protocols.ObservePoolKeys(d.opts.V4Pools, txLogs)
+	}
+
+	swaps, spoofed, unverified := d.txSwaps(txLogs)
+	for _, pool := range spoofed {
+		report.SpoofedPools = append(report.SpoofedPools, FlaggedPool{TxIndex: txIndex, Pool: pool})
+	}
+	for _, pool := range unverified {
+		report.UnverifiedPools = append(report.UnverifiedPools, FlaggedPool{TxIndex: txIndex, Pool: pool})
+	}
+	report.Classes = append(report.Classes, d.config.ClassifyTransaction(txLogs, swaps, d.opts.V4Pools))
+
+	for _, swap := range effectiveLegs(swaps) {
+		poolID := swap.PairID()
+		if _, ok := index.poolLegs[poolID]; !ok {
+			index.pools = append(index.pools, poolID)
+		}
+		index.poolLegs[poolID] = append(index.poolLegs[poolID], poolLeg{txIndex: txIndex, swap: swap})
 	}
 
-	poolDirections := make(map[common.Address][]bool)
-	for _, txLogs := range bundleLogs {
-		for _, swap := range protocols.ParseSwapEvents(txLogs) {
-			poolID := swap.PairID()
-			poolDirections[poolID] = append(poolDirections[poolID], swap.IsToken0To1())
+	for _, event := range protocols.ParseLiquidityEvents(txLogs) {
+		index.liquidityLegs[event.PairID()] = append(index.liquidityLegs[event.PairID()], liquidityLeg{txIndex: txIndex, event: event})
+	}
+}
+
+// txSwaps parses the swaps of one transaction according to the detector options, leaving
+// out swaps of spoofed pools, and of unverified pools unless KeepUnverifiedPools is set.
+// The spoofed and unverified pools are returned as well.
+func (d *Detector) txSwaps(txLogs []*types.Log) (swaps []protocols.SwapEvent, spoofed, unverified []common.Address) {
+	ignored := make(map[common.Address]bool)
+	if d.opts.PoolVerifier != nil {
+		spoofed, unverified = protocols.VerifyPools(txLogs, d.opts.PoolVerifier)
+		for _, pool := range spoofed {
+			ignored[pool] = true
+		}
+		if !d.opts.KeepUnverifiedPools {
+			for _, pool := range unverified {
+				ignored[pool] = true
+			}
+		}
+	}
+
+	for _, swap := range d.parseSwaps(txLogs) {
+		if !ignored[swap.PairID()] {
+			swaps = append(swaps, swap)
+		}
+	}
+	return swaps, spoofed, unverified
+}
+
+// effectiveLegs nets the swaps of one transaction per pool, so that a transaction trading
+// a pool both ways counts as a single leg and cannot complete a pattern by itself.
+// Round trips without a net direction are dropped.
+func effectiveLegs(swaps []protocols.SwapEvent) []protocols.SwapEvent {
+	var legs []protocols.SwapEvent
+	for _, swap := range protocols.NetSwaps(swaps) {
+		if net, ok := swap.(*protocols.NetSwap); ok && net.Neutral() {
+			continue
 		}
+		legs = append(legs, swap)
 	}
+	return legs
+}
+
+// findSandwich returns the first swap-direction sandwich pattern found on any pool.
+func (d *Detector) findSandwich(bundleLogs [][]*types.Log, pools []common.Address, poolLegs map[common.Address][]poolLeg, reserves *uniswapv2.PoolState) *SandwichError {
+	for _, pool := range pools {
+		legs := poolLegs[pool]
+		front, victim, back, ok := findSandwichPattern(legDirections(legs))
+		if !ok {
+			continue
+		}
 
-	for pool, directions := range poolDirections {
-		if hasSandwichPattern(directions) {
-			return fmt.Errorf("sandwich attack detected on pool: %s", pool.Hex())
+		sandwichErr := &SandwichError{
+			Pool:     pool,
+			FrontTx:  legs[front].txIndex,
+			VictimTx: legs[victim].txIndex,
+			BackTx:   legs[back].txIndex,
+		}
+		sandwichErr.Price = priceEvidence(reserves, pool, sandwichErr.FrontTx, sandwichErr.VictimTx, legs[victim].swap.IsToken0To1())
+		for _, leg := range legs[front+1 : back] {
+			if leg.swap.IsToken0To1() != legs[front].swap.IsToken0To1() {
+				continue
+			}
+			sandwichErr.Victims = append(sandwichErr.Victims, d.victim(bundleLogs, pool, leg))
+		}
+		sandwichErr.VictimRoute = sandwichErr.Victims[0].Route
+		for _, leg := range legs {
+			if protocols.IsHeuristic(leg.swap) {
+				sandwichErr.Heuristic = true
+			}
 		}
+		return sandwichErr
 	}
 
 	return nil
 }
 
+// victim describes the trade of a victim leg on the sandwiched pool.
+func (d *Detector) victim(bundleLogs [][]*types.Log, pool common.Address, leg poolLeg) Victim {
+	victimLogs := bundleLogs[leg.txIndex]
+	victimSwaps, _, _ := d.txSwaps(victimLogs)
+	return Victim{
+		TxIndex:   leg.txIndex,
+		AmountIn:  leg.swap.AmountIn(),
+		AmountOut: leg.swap.AmountOut(),
+		Route:     d.config.RouteForPool(victimLogs, victimSwaps, pool, d.opts.V4Pools),
+	}
+}
+
+// isBackrunBundle returns true if the first transaction swaps without arbitraging and every
+// later transaction is a cyclic arbitrage.
+func isBackrunBundle(classes []protocols.TxClass) bool {
+	if classes[0] != protocols.TxSingleSwap && classes[0] != protocols.TxMultiHop {
+		return false
+	}
+	for _, class := range classes[1:] {
+		if class != protocols.TxCyclicArbitrage {
+			return false
+		}
+	}
+	return true
+}
+
+// priceEvidence checks that the front-run moved the pool price against the victim,
+// using the reserves tracked from V2 Sync events. Returns nil if they are unknown.
+func priceEvidence(reserves *uniswapv2.PoolState, pool common.Address, frontTx, victimTx int, victimZeroForOne bool) *PriceEvidence {
+	frontSnapshots := reserves.TxSnapshots(pool, frontTx)
+	victimSnapshots := reserves.TxSnapshots(pool, victimTx)
+	if len(frontSnapshots) == 0 || len(victimSnapshots) == 0 {
+		return nil
+	}
+
+	front, victim := frontSnapshots[0], victimSnapshots[len(victimSnapshots)-1]
+	frontSpot, victimSpot := front.SpotPriceBefore(), victim.SpotPriceBefore()
+	if frontSpot == nil || victimSpot == nil {
+		return nil
+	}
+
+	evidence := &PriceEvidence{
+		FrontRunImpact:    front.PriceImpact(),
+		VictimPriceImpact: victim.PriceImpact(),
+	}
+	// Selling token0 gets fewer token1 per token0 once the price dropped, and vice versa.
+	if victimZeroForOne {
+		evidence.MovedAgainstVictim = victimSpot.Cmp(frontSpot) < 0
+	} else {
+		evidence.MovedAgainstVictim = victimSpot.Cmp(frontSpot) > 0
+	}
+	return evidence
+}
+
+// legDirections returns the swap direction of each leg.
+func legDirections(legs []poolLeg) []bool {
+	directions := make([]bool, len(legs))
+	for i, leg := range legs {
+		directions[i] = leg.swap.IsToken0To1()
+	}
+	return directions
+}
+
+// parseSwaps extracts the swaps of a single transaction according to the detector options.
+func (d *Detector) parseSwaps(txLogs []*types.Log) []protocols.SwapEvent {
+	if d.opts.TransferFallback {
+		return d.config.ParseSwapEventsWithFallback(txLogs)
+	}
+	return d.config.ParseSwapEvents(txLogs)
+}
+
 // hasSandwichPattern checks if swap directions form a sandwich attack pattern.
 func hasSandwichPattern(directions []bool) bool {
+	_, _, _, ok := findSandwichPattern(directions)
+	return ok
+}
+
+// findSandwichPattern returns the positions of the first front-run, victim and back-run
+// directions that form a sandwich attack pattern.
+func findSandwichPattern(directions []bool) (front, victim, back int, ok bool) {
 	n := len(directions)
 	if n < 3 {
-		return false
+		return 0, 0, 0, false
 	}
 
 	// Look for Buy-Buy-Sell or Sell-Sell-Buy patterns
@@ -45,15 +352,15 @@ func hasSandwichPattern(directions []bool) bool {
 			for k := j + 1; k < n; k++ {
 				// Buy-Buy-Sell pattern
 				if directions[i] && directions[j] && !directions[k] {
-					return true
+					return i, j, k, true
 				}
 				// Sell-Sell-Buy pattern
 				if !directions[i] && !directions[j] && directions[k] {
-					return true
+					return i, j, k, true
 				}
 			}
 		}
 	}
 
-	return false
+	return 0, 0, 0, false
 }
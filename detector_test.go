@@ -1,8 +1,12 @@
 package bscexorcist
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols"
+	"github.com/48Club/bscexorcist/protocols/poolverify"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -59,6 +63,719 @@ func TestDetectSandwichForBundle(t *testing.T) {
 	}
 }
 
+func TestDetectorTransferFallback(t *testing.T) {
+	var (
+		amm    = common.HexToAddress("0x7d8e5cE1d2D4b5A4F0d3E3C5a8b1E4a9C0f2D3b4")
+		usdt   = common.HexToAddress("0x55d398326f99059ff775485246999027b3197955")
+		token  = common.HexToAddress("0x4e3a5f2c1d9b7e8a6c0f1b2d3e4f5a6b7c8d4444")
+		bot    = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+		victim = common.HexToAddress("0xc7e53484f23333b722d47cc58e93f86806db6220")
+	)
+	// swapLogs emulates an unsupported AMM: the pool pulls tokenIn, pays tokenOut and emits an unknown event.
+	swapLogs := func(trader, tokenIn, tokenOut common.Address, amountIn, amountOut int64) []*types.Log {
+		return []*types.Log{
+			logtest.Transfer(tokenIn, trader, amm, amountIn),
+			logtest.Transfer(tokenOut, amm, trader, amountOut),
+			{Address: amm, Topics: []common.Hash{common.HexToHash("0x01")}},
+		}
+	}
+	bundle := [][]*types.Log{
+		swapLogs(bot, usdt, token, 1000, 500),
+		swapLogs(victim, usdt, token, 100, 45),
+		swapLogs(bot, token, usdt, 500, 1050),
+	}
+
+	if err := DetectSandwichForBundle(bundle); err != nil {
+		t.Errorf("DetectSandwichForBundle() without fallback error = %v, want nil", err)
+	}
+
+	err := NewDetector(Options{TransferFallback: true}).DetectSandwichForBundle(bundle)
+	var sandwichErr *SandwichError
+	if !errors.As(err, &sandwichErr) {
+		t.Fatalf("DetectSandwichForBundle() with fallback error = %v, want *SandwichError", err)
+	}
+	if sandwichErr.Pool != amm || !sandwichErr.Heuristic {
+		t.Errorf("DetectSandwichForBundle() with fallback = %+v, want heuristic detection on %s", sandwichErr, amm.Hex())
+	}
+
+	// The same AMM paying out native BNB by unwrapping WBNB is seen through the Withdrawal event.
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	withdrawal := func(amount int64) *types.Log {
+		return logtest.Log(wbnb, "Withdrawal(address,uint256)", []common.Hash{logtest.AddressTopic(amm)}, logtest.Word(amount))
+	}
+	nativeBundle := [][]*types.Log{
+		swapLogs(bot, wbnb, token, 1000, 500),
+		swapLogs(victim, wbnb, token, 100, 45),
+		{
+			logtest.Transfer(token, bot, amm, 500),
+			withdrawal(1050),
+			{Address: amm, Topics: []common.Hash{common.HexToHash("0x01")}},
+		},
+	}
+	if err := NewDetector(Options{TransferFallback: true}).DetectSandwichForBundle(nativeBundle); err == nil {
+		t.Error("DetectSandwichForBundle() with native payout error = nil, want sandwich")
+	}
+}
+
+func TestSandwichErrorVictimRoute(t *testing.T) {
+	// testCase0 with the victim trading through a 1inch-style router.
+	routeLog := &types.Log{
+		Address: common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582"),
+		Topics: []common.Hash{
+			common.HexToHash("0xd6d4f5681c246c9f42c203e287975af1601f8df8035a9251f79aab5c8f09e2f8"),
+		},
+		Data: hexutil.MustDecode("0x000000000000000000000000c7e53484f23333b722d47cc58e93f86806db6220000000000000000000000000bb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c00000000000000000000000055d398326f99059ff775485246999027b3197955000000000000000000000000c7e53484f23333b722d47cc58e93f86806db62200000000000000000000000000000000000000000000000001bc16d674ec8000000000000000000000000000000000000000000000000003ff7ca241790f00000"),
+	}
+	// The router pays WBNB into the pool and USDT out to the receiver.
+	pool, router := testCase0[1][0].Address, routeLog.Address
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	victimTx := []*types.Log{
+		logtest.Transfer(wbnb, router, pool, 2000000000000000000),
+		testCase0[1][0],
+		logtest.Transfer(usdt, pool, common.HexToAddress("0xc7e53484f23333b722d47cc58e93f86806db6220"), 1180000000000000000),
+		routeLog,
+	}
+	bundle := [][]*types.Log{testCase0[0], victimTx, testCase0[2]}
+
+	var sandwichErr *SandwichError
+	if err := DetectSandwichForBundle(bundle); !errors.As(err, &sandwichErr) {
+		t.Fatalf("DetectSandwichForBundle() error = %v, want *SandwichError", err)
+	}
+	if sandwichErr.FrontTx != 0 || sandwichErr.VictimTx != 1 || sandwichErr.BackTx != 2 {
+		t.Errorf("DetectSandwichForBundle() legs = %d/%d/%d, want 0/1/2", sandwichErr.FrontTx, sandwichErr.VictimTx, sandwichErr.BackTx)
+	}
+	route := sandwichErr.VictimRoute
+	if route == nil || route.ReturnAmount.String() != "1180000000000000000000" || route.RealizedRate().Text('f', 0) != "590" {
+		t.Errorf("DetectSandwichForBundle() victim route = %+v", route)
+	}
+
+	// A route trading tokens the pool did not transfer is not the victim's trade on the pool.
+	bundle[1] = []*types.Log{testCase0[1][0], routeLog}
+	if err := DetectSandwichForBundle(bundle); !errors.As(err, &sandwichErr) || sandwichErr.VictimRoute != nil {
+		t.Errorf("DetectSandwichForBundle() unrelated route = %v, want a sandwich without victim route", err)
+	}
+}
+
+func TestSandwichErrorPriceEvidence(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	bundle := [][]*types.Log{
+		v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091),
+		v2SwapLogs(pool, 50000, 0, 0, 39525, 1150000, 869566),
+		v2SwapLogs(pool, 0, 90909, 104444, 0, 1045556, 960475),
+	}
+
+	var sandwichErr *SandwichError
+	if err := DetectSandwichForBundle(bundle); !errors.As(err, &sandwichErr) {
+		t.Fatalf("DetectSandwichForBundle() error = %v, want *SandwichError", err)
+	}
+	price := sandwichErr.Price
+	if price == nil || !price.MovedAgainstVictim {
+		t.Fatalf("DetectSandwichForBundle() price evidence = %+v, want price moved against victim", price)
+	}
+	if impact, _ := price.FrontRunImpact.Float64(); impact < 0.17 || impact > 0.18 {
+		t.Errorf("DetectSandwichForBundle() front-run impact = %v, want ~0.174", impact)
+	}
+}
+
+func TestDetectorPoolVerifier(t *testing.T) {
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	swapTx := func(pool common.Address, zeroForOne bool) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(usdt, trader, pool, 1000), logtest.Transfer(wbnb, pool, trader, 1)}
+		if zeroForOne {
+			return append(logs, v2SwapLogs(pool, 1000, 0, 0, 1, 100000, 100)...)
+		}
+		return append(logs, v2SwapLogs(pool, 0, 1000, 1, 0, 100000, 100)...)
+	}
+	detector := NewDetector(Options{PoolVerifier: poolverify.NewVerifier(poolverify.DefaultFactories)})
+
+	genuine := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	report := detector.Analyze([][]*types.Log{swapTx(genuine, true), swapTx(genuine, true), swapTx(genuine, false)})
+	if report.Sandwich == nil || len(report.SpoofedPools) != 0 {
+		t.Errorf("Analyze() genuine pool = %+v, want sandwich and no spoofed pools", report)
+	}
+
+	// A pool of an unlisted factory trades tokens like a pool: it is listed as unverified
+	// and its swaps are only analyzed on request.
+	fork := common.HexToAddress("0x00000000000000000000000000000000000f0f0f")
+	forkBundle := [][]*types.Log{swapTx(fork, true), swapTx(fork, true), swapTx(fork, false)}
+	report = detector.Analyze(forkBundle)
+	if report.Err() != nil || len(report.SpoofedPools) != 0 {
+		t.Errorf("Analyze() unverified pool = %+v, want no error and no spoofed pools", report)
+	}
+	if len(report.UnverifiedPools) != 3 || report.UnverifiedPools[0].Pool != fork {
+		t.Errorf("Analyze() unverified pools = %+v, want %s in every tx", report.UnverifiedPools, fork)
+	}
+	keeping := NewDetector(Options{PoolVerifier: poolverify.NewVerifier(poolverify.DefaultFactories), KeepUnverifiedPools: true})
+	if report := keeping.Analyze(forkBundle); report.Sandwich == nil {
+		t.Errorf("Analyze() kept unverified pool = %+v, want sandwich", report)
+	}
+
+	// An emitter logging swaps without moving any token is no pool at all.
+	fake := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	spoofed := [][]*types.Log{
+		v2SwapLogs(fake, 1000, 0, 0, 1, 100000, 100),
+		v2SwapLogs(fake, 1000, 0, 0, 1, 100000, 100),
+		v2SwapLogs(fake, 0, 1000, 1, 0, 100000, 100),
+	}
+	report = detector.Analyze(spoofed)
+	if report.Err() != nil {
+		t.Errorf("Analyze() spoofed pool error = %v, want nil", report.Err())
+	}
+	if len(report.SpoofedPools) != 3 || report.SpoofedPools[0].Pool != fake || len(report.UnverifiedPools) != 0 {
+		t.Errorf("Analyze() spoofed pools = %+v, want %s in every tx", report.SpoofedPools, fake)
+	}
+	if err := DetectSandwichForBundle(spoofed); err == nil {
+		t.Error("DetectSandwichForBundle() without verifier error = nil, want sandwich")
+	}
+}
+
+func TestDetectJITLiquidity(t *testing.T) {
+	pool := common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+	bot := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	// owner, tickLower -10, tickUpper 10
+	position := []common.Hash{logtest.AddressTopic(bot), logtest.IntTopic(-10), logtest.IntTopic(10)}
+
+	mint := logtest.Log(pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.AddressWord(bot), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000))
+	swap := logtest.Log(pool, "Swap(address,address,int256,int256,uint160,uint128,int24)", []common.Hash{{}, {}},
+		logtest.Word(300), logtest.Word(-290), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(0))
+	burn := logtest.Log(pool, "Burn(address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.Word(5000), logtest.Word(1290), logtest.Word(1710))
+	collect := logtest.Log(pool, "Collect(address,address,int24,int24,uint128,uint128)", position,
+		logtest.AddressWord(bot), logtest.Word(1291), logtest.Word(1710))
+
+	var jitErr *JITLiquidityError
+	err := DetectSandwichForBundle([][]*types.Log{{mint}, {swap}, {burn, collect}})
+	if !errors.As(err, &jitErr) {
+		t.Fatalf("DetectSandwichForBundle() error = %v, want *JITLiquidityError", err)
+	}
+	if jitErr.Pool != pool || jitErr.Provider != bot || jitErr.MintTx != 0 || jitErr.VictimTx != 1 || jitErr.BurnTx != 2 {
+		t.Errorf("DetectSandwichForBundle() JIT = %+v", jitErr)
+	}
+	if jitErr.Fee0 == nil || jitErr.Fee0.Int64() != 1 || jitErr.Fee1.Int64() != 0 {
+		t.Errorf("DetectSandwichForBundle() JIT fees = %v, %v, want 1, 0", jitErr.Fee0, jitErr.Fee1)
+	}
+
+	if err := DetectSandwichForBundle([][]*types.Log{{mint}, {burn, collect}, {swap}}); err != nil {
+		t.Errorf("DetectSandwichForBundle() liquidity removed before the swap error = %v, want nil", err)
+	}
+	if jitErr.Score == nil || jitErr.Score.Verdict != VerdictSandwich {
+		t.Errorf("DetectSandwichForBundle() JIT score = %+v, want sandwich", jitErr.Score)
+	}
+
+	// Positions held through a position manager share the manager's pool position key.
+	manager := common.HexToAddress("0x46A15B0b27311cedF172AB29E4f4766fbE7F4364")
+	managed := []common.Hash{logtest.AddressTopic(manager), logtest.IntTopic(-10), logtest.IntTopic(10)}
+	nftMint := []*types.Log{
+		logtest.Log(pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", managed,
+			logtest.AddressWord(manager), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+		logtest.Log(manager, "Transfer(address,address,uint256)",
+			[]common.Hash{logtest.AddressTopic(common.Address{}), logtest.AddressTopic(bot), logtest.IntTopic(42)}),
+		logtest.Log(manager, "IncreaseLiquidity(uint256,uint128,uint256,uint256)",
+			[]common.Hash{logtest.IntTopic(42)}, logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+	}
+	nftBurn := func(tokenID int64) []*types.Log {
+		return []*types.Log{
+			logtest.Log(pool, "Burn(address,int24,int24,uint128,uint256,uint256)", managed,
+				logtest.Word(5000), logtest.Word(1290), logtest.Word(1710)),
+			logtest.Log(manager, "DecreaseLiquidity(uint256,uint128,uint256,uint256)",
+				[]common.Hash{logtest.IntTopic(tokenID)}, logtest.Word(5000), logtest.Word(1290), logtest.Word(1710)),
+		}
+	}
+	err = DetectSandwichForBundle([][]*types.Log{nftMint, {swap}, nftBurn(42)})
+	if !errors.As(err, &jitErr) || jitErr.Provider != bot {
+		t.Errorf("DetectSandwichForBundle() NFT position error = %v, want JIT by the NFT holder", err)
+	}
+	if err := DetectSandwichForBundle([][]*types.Log{nftMint, {swap}, nftBurn(43)}); err != nil {
+		t.Errorf("DetectSandwichForBundle() another NFT of the range error = %v, want nil", err)
+	}
+
+	// V2 liquidity is fungible: a deposit and a withdrawal only pair up for the same owner.
+	v2Pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	router := common.HexToAddress("0x10ED43C718714eb63d5aA57B78B54704E256024E")
+	alice := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	bob := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	v2Mint := []*types.Log{
+		logtest.Transfer(v2Pool, common.Address{}, alice, 1000),
+		logtest.Log(v2Pool, "Mint(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(router)}, logtest.Word(1000), logtest.Word(1000)),
+	}
+	v2Burn := func(owner common.Address) []*types.Log {
+		return []*types.Log{logtest.Log(v2Pool, "Burn(address,uint256,uint256,address)", []common.Hash{logtest.AddressTopic(router), logtest.AddressTopic(owner)},
+			logtest.Word(1000), logtest.Word(1000))}
+	}
+	v2Swap := v2SwapLogs(v2Pool, 100, 0, 0, 90, 2100, 1910)
+	if err := DetectSandwichForBundle([][]*types.Log{v2Mint, v2Swap, v2Burn(bob)}); err != nil {
+		t.Errorf("DetectSandwichForBundle() V2 liquidity of different owners error = %v, want nil", err)
+	}
+	report := NewDetector(Options{}).Analyze([][]*types.Log{v2Mint, v2Swap, v2Burn(alice)})
+	if report.JIT == nil || report.JIT.Provider != alice {
+		t.Fatalf("Analyze() V2 JIT = %+v, want JIT by the owner", report.JIT)
+	}
+	for _, signal := range report.JIT.Score.Signals {
+		if signal.Name == "same position" {
+			t.Errorf("Analyze() V2 JIT signals = %+v, want no same position signal", report.JIT.Score.Signals)
+		}
+	}
+}
+
+func TestDetectLiquidityRemoval(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	router := common.HexToAddress("0x10ED43C718714eb63d5aA57B78B54704E256024E")
+	provider := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+
+	remove := []*types.Log{
+		logtest.V2Sync(pool, 500000, 500000),
+		logtest.Log(pool, "Burn(address,uint256,uint256,address)", []common.Hash{logtest.AddressTopic(router), logtest.AddressTopic(provider)},
+			logtest.Word(500000), logtest.Word(500000)),
+	}
+	victim := v2SwapLogs(pool, 10000, 0, 0, 9803, 510000, 490197)
+	add := []*types.Log{
+		logtest.Transfer(pool, common.Address{}, provider, 500000),
+		logtest.V2Sync(pool, 1010000, 990197),
+		logtest.Log(pool, "Mint(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(router)}, logtest.Word(500000), logtest.Word(490197)),
+	}
+
+	var removalErr *LiquidityRemovalError
+	err := DetectSandwichForBundle([][]*types.Log{remove, victim, add})
+	if !errors.As(err, &removalErr) {
+		t.Fatalf("DetectSandwichForBundle() error = %v, want *LiquidityRemovalError", err)
+	}
+	if removalErr.Provider != provider || removalErr.RemoveTx != 0 || removalErr.VictimTx != 1 || removalErr.AddTx != 2 {
+		t.Errorf("DetectSandwichForBundle() liquidity removal = %+v", removalErr)
+	}
+	// 10000 * 1000000 / 1010000 - 10000 * 500000 / 510000
+	if removalErr.ExtraSlippage == nil || removalErr.ExtraSlippage.Int64() != 97 {
+		t.Errorf("DetectSandwichForBundle() extra slippage = %v, want 97", removalErr.ExtraSlippage)
+	}
+	if removalErr.Score == nil || removalErr.Score.Verdict == VerdictClean {
+		t.Errorf("DetectSandwichForBundle() liquidity removal score = %+v, want a verdict", removalErr.Score)
+	}
+
+	// Positions held through a position manager are matched by NFT.
+	v3Pool := common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+	manager := common.HexToAddress("0x46A15B0b27311cedF172AB29E4f4766fbE7F4364")
+	managed := []common.Hash{logtest.AddressTopic(manager), logtest.IntTopic(-10), logtest.IntTopic(10)}
+	decrease := []*types.Log{
+		logtest.Log(v3Pool, "Burn(address,int24,int24,uint128,uint256,uint256)", managed,
+			logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+		logtest.Log(manager, "DecreaseLiquidity(uint256,uint128,uint256,uint256)",
+			[]common.Hash{logtest.IntTopic(42)}, logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+	}
+	v3Swap := []*types.Log{logtest.Log(v3Pool, "Swap(address,address,int256,int256,uint160,uint128,int24)", []common.Hash{{}, {}},
+		logtest.Word(300), logtest.Word(-290), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(0))}
+	increase := func(tokenID int64) []*types.Log {
+		return []*types.Log{
+			logtest.Log(v3Pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", managed,
+				logtest.AddressWord(manager), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+			logtest.Log(manager, "IncreaseLiquidity(uint256,uint128,uint256,uint256)",
+				[]common.Hash{logtest.IntTopic(tokenID)}, logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+		}
+	}
+	report := NewDetector(Options{}).Analyze([][]*types.Log{decrease, v3Swap, increase(42)})
+	if report.LiquidityRemoval == nil || report.LiquidityRemoval.Score.Verdict == VerdictClean {
+		t.Errorf("Analyze() same NFT liquidity removal = %+v, want detected", report.LiquidityRemoval)
+	}
+	if err := DetectSandwichForBundle([][]*types.Log{decrease, v3Swap, increase(43)}); err != nil {
+		t.Errorf("DetectSandwichForBundle() another NFT of the range error = %v, want nil", err)
+	}
+}
+
+func TestDetectAddLiquiditySandwich(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	router := common.HexToAddress("0x10ED43C718714eb63d5aA57B78B54704E256024E")
+	depositor := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+
+	deposit := []*types.Log{
+		logtest.Transfer(pool, common.Address{}, depositor, 100000),
+		logtest.V2Sync(pool, 1210000, 1000000),
+		logtest.Log(pool, "Mint(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(router)}, logtest.Word(110000), logtest.Word(90909)),
+	}
+	token0 := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	token1 := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	front := func(trader common.Address) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(token0, trader, pool, 100000), logtest.Transfer(token1, pool, trader, 90909)}
+		return append(logs, v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091)...)
+	}
+	back := func(trader common.Address) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(token1, trader, pool, 90909), logtest.Transfer(token0, pool, trader, 100000)}
+		return append(logs, v2SwapLogs(pool, 0, 90909, 100000, 0, 1110000, 1090909)...)
+	}
+	attacker := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	bundle := [][]*types.Log{front(attacker), deposit, back(attacker)}
+
+	var addErr *AddLiquiditySandwichError
+	if err := DetectSandwichForBundle(bundle); !errors.As(err, &addErr) {
+		t.Fatalf("DetectSandwichForBundle() error = %v, want *AddLiquiditySandwichError", err)
+	}
+	if addErr.Provider != depositor || addErr.FrontTx != 0 || addErr.VictimTx != 1 || addErr.BackTx != 2 {
+		t.Errorf("DetectSandwichForBundle() add-liquidity sandwich = %+v", addErr)
+	}
+	// 110000 + 90909 - 2 * sqrt(110000 * 90909) at the pre-front-run price of 1
+	if lost, _ := addErr.ValueLost.Float64(); lost < 909 || lost > 910 {
+		t.Errorf("DetectSandwichForBundle() value lost = %v, want ~909", lost)
+	}
+	if addErr.Score == nil || addErr.Score.Verdict != VerdictSandwich {
+		t.Errorf("DetectSandwichForBundle() add-liquidity sandwich score = %+v, want sandwich", addErr.Score)
+	}
+
+	// Opposite trades of unrelated traders around a deposit are not an attack.
+	other := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	if err := DetectSandwichForBundle([][]*types.Log{front(attacker), deposit, back(other)}); err != nil {
+		t.Errorf("DetectSandwichForBundle() unrelated traders error = %v, want nil", err)
+	}
+
+	// A position minted through a position manager names its NFT holder as the victim.
+	v3Pool := common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+	manager := common.HexToAddress("0x46A15B0b27311cedF172AB29E4f4766fbE7F4364")
+	v3Swap := func(amount0, amount1 int64) []*types.Log {
+		in, out, amountIn, amountOut := token0, token1, amount0, -amount1
+		if amount0 < 0 {
+			in, out, amountIn, amountOut = token1, token0, amount1, -amount0
+		}
+		return []*types.Log{
+			logtest.Transfer(in, attacker, v3Pool, amountIn),
+			logtest.Transfer(out, v3Pool, attacker, amountOut),
+			logtest.Log(v3Pool, "Swap(address,address,int256,int256,uint160,uint128,int24)", []common.Hash{{}, {}},
+				logtest.Word(amount0), logtest.Word(amount1), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(0)),
+		}
+	}
+	managed := []common.Hash{logtest.AddressTopic(manager), logtest.IntTopic(-10), logtest.IntTopic(10)}
+	nftDeposit := []*types.Log{
+		logtest.Log(v3Pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", managed,
+			logtest.AddressWord(manager), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+		logtest.Log(manager, "Transfer(address,address,uint256)",
+			[]common.Hash{logtest.AddressTopic(common.Address{}), logtest.AddressTopic(depositor), logtest.IntTopic(7)}),
+		logtest.Log(manager, "IncreaseLiquidity(uint256,uint128,uint256,uint256)",
+			[]common.Hash{logtest.IntTopic(7)}, logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+	}
+	err := DetectSandwichForBundle([][]*types.Log{v3Swap(300, -290), nftDeposit, v3Swap(-290, 299)})
+	if !errors.As(err, &addErr) || addErr.Provider != depositor {
+		t.Errorf("DetectSandwichForBundle() position manager deposit error = %v, want the NFT holder as provider", err)
+	}
+}
+
+func TestBackrunBundleIsClean(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	user := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	searcher := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+
+	buy := func(trader common.Address) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(usdt, trader, pool, 1000), logtest.Transfer(wbnb, pool, trader, 3)}
+		return append(logs, v2SwapLogs(pool, 1000, 0, 0, 3, 101000, 297)...)
+	}
+	sell := func(trader common.Address) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(wbnb, trader, pool, 3), logtest.Transfer(usdt, pool, trader, 1001)}
+		return append(logs, v2SwapLogs(pool, 0, 3, 1001, 0, 99999, 300)...)
+	}
+	// The searcher round-trips the pool, USDT -> WBNB -> USDT.
+	arbitrage := append(buy(searcher), sell(searcher)...)
+
+	report := NewDetector(Options{}).Analyze([][]*types.Log{buy(user), arbitrage, arbitrage})
+	if err := report.Err(); err != nil {
+		t.Errorf("Analyze() backrun bundle error = %v, want nil", err)
+	}
+	want := []protocols.TxClass{protocols.TxSingleSwap, protocols.TxCyclicArbitrage, protocols.TxCyclicArbitrage}
+	if len(report.Classes) != len(want) {
+		t.Fatalf("Analyze() classes = %v, want %v", report.Classes, want)
+	}
+	for i := range want {
+		if report.Classes[i] != want[i] {
+			t.Errorf("Analyze() class of tx %d = %v, want %v", i, report.Classes[i], want[i])
+		}
+	}
+
+	if err := DetectSandwichForBundle([][]*types.Log{buy(searcher), buy(user), sell(searcher)}); err == nil {
+		t.Error("DetectSandwichForBundle() sandwich error = nil, want sandwich")
+	}
+
+	// Liquidity withdrawn and re-added around the arbitrages is still an attack.
+	router := common.HexToAddress("0x10ED43C718714eb63d5aA57B78B54704E256024E")
+	remove := append([]*types.Log{
+		logtest.Log(pool, "Burn(address,uint256,uint256,address)", []common.Hash{logtest.AddressTopic(router), logtest.AddressTopic(user)},
+			logtest.Word(50000), logtest.Word(150)),
+	}, buy(user)...)
+	// The searcher buys on the pool and sells on another one.
+	pool2 := common.HexToAddress("0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16")
+	crossArbitrage := append(buy(searcher),
+		logtest.Transfer(wbnb, searcher, pool2, 3), logtest.Transfer(usdt, pool2, searcher, 1001),
+		logtest.V2Swap(pool2, searcher, 0, 3, 1001, 0))
+	add := append(append([]*types.Log{}, crossArbitrage...),
+		logtest.Transfer(pool, common.Address{}, user, 2000),
+		logtest.Log(pool, "Mint(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(router)}, logtest.Word(50000), logtest.Word(150)))
+	report = NewDetector(Options{}).Analyze([][]*types.Log{remove, crossArbitrage, add})
+	if report.Sandwich != nil || report.LiquidityRemoval == nil {
+		t.Errorf("Analyze() backrun bundle with liquidity removal = %+v, want only the liquidity removal", report)
+	}
+}
+
+func TestSwapsNettedPerTransaction(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	buy := v2SwapLogs(pool, 1000, 0, 0, 3, 101000, 297)
+	sell := v2SwapLogs(pool, 0, 3, 1001, 0, 99999, 300)
+	zap := append(append([]*types.Log{}, buy...), sell...)
+
+	// Without netting the zap's buy and sell complete Buy-Buy-Sell with the first buy.
+	if err := DetectSandwichForBundle([][]*types.Log{buy, zap, buy}); err != nil {
+		t.Errorf("DetectSandwichForBundle() round trip in one tx error = %v, want nil", err)
+	}
+
+	swaps := protocols.NetSwaps(protocols.ParseSwapEvents(append(append(zap, buy...), buy...)))
+	if len(swaps) != 1 {
+		t.Fatalf("NetSwaps() = %d swaps, want 1", len(swaps))
+	}
+	net, ok := swaps[0].(*protocols.NetSwap)
+	if !ok || len(net.Legs()) != 4 || net.Neutral() || !net.IsToken0To1() {
+		t.Fatalf("NetSwaps() = %+v, want a net token0 -> token1 swap of 4 legs", swaps[0])
+	}
+	if net.AmountIn().Int64() != 1999 || net.AmountOut().Int64() != 6 {
+		t.Errorf("NetSwaps() amounts = %v -> %v, want 1999 -> 6", net.AmountIn(), net.AmountOut())
+	}
+}
+
+func TestSandwichErrorVictims(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	bundle := [][]*types.Log{
+		v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091),
+		v2SwapLogs(pool, 50000, 0, 0, 39525, 1150000, 869566),
+		v2SwapLogs(pool, 20000, 0, 0, 14864, 1170000, 854702),
+		v2SwapLogs(pool, 0, 90909, 102270, 0, 1067730, 945611),
+	}
+
+	var sandwichErr *SandwichError
+	if err := DetectSandwichForBundle(bundle); !errors.As(err, &sandwichErr) {
+		t.Fatalf("DetectSandwichForBundle() error = %v, want *SandwichError", err)
+	}
+	if sandwichErr.FrontTx != 0 || sandwichErr.BackTx != 3 || len(sandwichErr.Victims) != 2 {
+		t.Fatalf("DetectSandwichForBundle() sandwich = %+v, want victims 1 and 2 between 0 and 3", sandwichErr)
+	}
+	for i, want := range []struct{ in, out int64 }{{50000, 39525}, {20000, 14864}} {
+		victim := sandwichErr.Victims[i]
+		if victim.TxIndex != i+1 || victim.AmountIn.Int64() != want.in || victim.AmountOut.Int64() != want.out {
+			t.Errorf("DetectSandwichForBundle() victim %d = %+v, want tx %d trading %d for %d", i, victim, i+1, want.in, want.out)
+		}
+	}
+}
+
+func TestSandwichScore(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	bot := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	user := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	withTransfers := func(trader, tokenIn, tokenOut common.Address, amountIn, amountOut int64, logs []*types.Log) []*types.Log {
+		return append([]*types.Log{logtest.Transfer(tokenIn, trader, pool, amountIn), logtest.Transfer(tokenOut, pool, trader, amountOut)}, logs...)
+	}
+
+	bundle := [][]*types.Log{
+		withTransfers(bot, usdt, wbnb, 100000, 90909, v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091)),
+		withTransfers(user, usdt, wbnb, 50000, 39525, v2SwapLogs(pool, 50000, 0, 0, 39525, 1150000, 869566)),
+		withTransfers(bot, wbnb, usdt, 90909, 104444, v2SwapLogs(pool, 0, 90909, 104444, 0, 1045556, 960475)),
+	}
+	report := NewDetector(Options{KnownBots: []common.Address{bot}}).Analyze(bundle)
+	if report.Verdict != VerdictSandwich || report.Sandwich == nil || report.Sandwich.Score.Total != 12 {
+		t.Errorf("Analyze() verdict = %v, score = %+v, want sandwich scoring 12", report.Verdict, report.Sandwich.Score)
+	}
+
+	// The back-run neither matches the front-run nor makes a profit.
+	bundle = [][]*types.Log{
+		v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091),
+		v2SwapLogs(pool, 50000, 0, 0, 39525, 1150000, 869566),
+		v2SwapLogs(pool, 0, 10000, 11000, 0, 1139000, 879566),
+	}
+	report = NewDetector(Options{}).Analyze(bundle)
+	if report.Verdict != VerdictSuspicious || report.Err() == nil {
+		t.Errorf("Analyze() weak pattern verdict = %v, error = %v, want suspicious with error", report.Verdict, report.Err())
+	}
+	report = NewDetector(Options{SuspiciousScore: 5, SandwichScore: 8}).Analyze(bundle)
+	if report.Verdict != VerdictClean || report.Err() != nil {
+		t.Errorf("Analyze() weak pattern with raised thresholds verdict = %v, error = %v, want clean", report.Verdict, report.Err())
+	}
+}
+
+func TestBlockAnalyzer(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	other := common.HexToAddress("0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	attacker := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	frontBy := func(trader common.Address) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(usdt, trader, pool, 100000), logtest.Transfer(wbnb, pool, trader, 90909)}
+		return append(logs, v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091)...)
+	}
+	backBy := func(trader common.Address) []*types.Log {
+		logs := []*types.Log{logtest.Transfer(wbnb, trader, pool, 90909), logtest.Transfer(usdt, pool, trader, 104444)}
+		return append(logs, v2SwapLogs(pool, 0, 90909, 104444, 0, 1045556, 960475)...)
+	}
+	front, back := frontBy(attacker), backBy(attacker)
+	victim := v2SwapLogs(pool, 50000, 0, 0, 39525, 1150000, 869566)
+	unrelated := v2SwapLogs(other, 1000, 0, 0, 3, 101000, 297)
+
+	units := []BlockUnit{
+		{BundleID: "front", Logs: [][]*types.Log{unrelated, front}},
+		{Logs: [][]*types.Log{victim}},
+		{BundleID: "clean", Logs: [][]*types.Log{unrelated}},
+		{BundleID: "back", Logs: [][]*types.Log{back}},
+	}
+	for i, unit := range units {
+		if err := DetectSandwichForBundle(unit.Logs); err != nil {
+			t.Fatalf("DetectSandwichForBundle() unit %d error = %v, want nil", i, err)
+		}
+	}
+
+	report := NewBlockAnalyzer(Options{}).Analyze(units)
+	if len(report.Sandwiches) != 1 {
+		t.Fatalf("Analyze() sandwiches = %d, want 1", len(report.Sandwiches))
+	}
+	sandwich := report.Sandwiches[0]
+	if sandwich.Pool != pool || sandwich.Front != (TxRef{Unit: 0, Tx: 1}) || sandwich.Back != (TxRef{Unit: 3, Tx: 0}) ||
+		len(sandwich.Victims) != 1 || sandwich.Victims[0] != (TxRef{Unit: 1, Tx: 0}) {
+		t.Errorf("Analyze() sandwich = %+v", sandwich)
+	}
+	if len(report.Exclude) != 2 || report.Exclude[0] != 0 || report.Exclude[1] != 3 {
+		t.Errorf("Analyze() exclude = %v, want [0 3]", report.Exclude)
+	}
+
+	loose := []BlockUnit{{Logs: [][]*types.Log{front}}, {Logs: [][]*types.Log{victim}}, {Logs: [][]*types.Log{back}}}
+	if report := NewBlockAnalyzer(Options{}).Analyze(loose); len(report.Sandwiches) != 0 {
+		t.Errorf("Analyze() loose transactions sandwiches = %+v, want none", report.Sandwiches)
+	}
+
+	// Bundles of unrelated traders in opposite directions around a loose transaction are not a sandwich.
+	trader := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	unrelatedBundles := []BlockUnit{
+		{BundleID: "buy", Logs: [][]*types.Log{frontBy(attacker)}},
+		{Logs: [][]*types.Log{victim}},
+		{BundleID: "sell", Logs: [][]*types.Log{backBy(trader)}},
+	}
+	if report := NewBlockAnalyzer(Options{}).Analyze(unrelatedBundles); len(report.Sandwiches) != 0 || len(report.Exclude) != 0 {
+		t.Errorf("Analyze() unrelated bundles = %+v, want no sandwich", report)
+	}
+}
+
+func TestIncrementalDetector(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	buy := v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091)
+	sell := v2SwapLogs(pool, 0, 90909, 104444, 0, 1045556, 960475)
+
+	detector := NewIncrementalDetector(Options{})
+	if err := detector.AddTx(buy).Err(); err != nil {
+		t.Fatalf("AddTx() front-run error = %v, want nil", err)
+	}
+	snapshot := detector.Snapshot()
+	if err := detector.AddTx(buy).Err(); err != nil {
+		t.Fatalf("AddTx() victim error = %v, want nil", err)
+	}
+
+	var sandwichErr *SandwichError
+	report := detector.AddTx(sell)
+	if err := report.Err(); !errors.As(err, &sandwichErr) {
+		t.Fatalf("AddTx() back-run error = %v, want *SandwichError", err)
+	}
+	if sandwichErr.Pool != pool || sandwichErr.FrontTx != 0 || sandwichErr.VictimTx != 1 || sandwichErr.BackTx != 2 {
+		t.Errorf("AddTx() sandwich = %+v", sandwichErr)
+	}
+	if report.Verdict != VerdictSandwich || sandwichErr.Score == nil {
+		t.Errorf("AddTx() verdict = %v, score = %+v, want a scored sandwich", report.Verdict, sandwichErr.Score)
+	}
+
+	// Rolling back the victim leaves a single buy before the sell.
+	if err := detector.Revert(snapshot); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if detector.Len() != 1 || detector.Err() != nil {
+		t.Fatalf("Revert() len = %d, error = %v, want 1 and nil", detector.Len(), detector.Err())
+	}
+	if err := detector.Revert(snapshot); err == nil {
+		t.Error("Revert() reverted snapshot error = nil, want error")
+	}
+	if err := detector.AddTx(sell).Err(); err != nil {
+		t.Errorf("AddTx() after revert error = %v, want nil", err)
+	}
+	if err := detector.AddTx(sell).Err(); err != nil {
+		t.Errorf("AddTx() second sell error = %v, want nil", err)
+	}
+	if err := detector.AddTx(buy).Err(); !errors.As(err, &sandwichErr) || sandwichErr.FrontTx != 1 || sandwichErr.VictimTx != 2 || sandwichErr.BackTx != 3 {
+		t.Errorf("AddTx() Sell-Sell-Buy error = %v, want sandwich of txs 1, 2, 3", err)
+	}
+
+	// On a busy pool only the legs right before the back-run make up the sandwich.
+	detector = NewIncrementalDetector(Options{})
+	for i := 0; i < 100; i++ {
+		detector.AddTx(buy)
+		if report := detector.AddTx(sell); report.Sandwich != nil {
+			t.Fatalf("AddTx() alternating legs sandwich = %+v, want nil", report.Sandwich)
+		}
+	}
+	detector.AddTx(buy)
+	detector.AddTx(buy)
+	report = detector.AddTx(sell)
+	if sandwichErr := report.Sandwich; sandwichErr == nil || sandwichErr.FrontTx != 200 || sandwichErr.VictimTx != 201 || sandwichErr.BackTx != 202 {
+		t.Errorf("AddTx() busy pool sandwich = %+v, want txs 200, 201, 202", report.Sandwich)
+	}
+
+	// Liquidity attacks are checked as well.
+	v3Pool := common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+	bot := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	position := []common.Hash{logtest.AddressTopic(bot), logtest.IntTopic(-10), logtest.IntTopic(10)}
+	detector = NewIncrementalDetector(Options{})
+	detector.AddTx([]*types.Log{logtest.Log(v3Pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.AddressWord(bot), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000))})
+	detector.AddTx([]*types.Log{logtest.Log(v3Pool, "Swap(address,address,int256,int256,uint160,uint128,int24)", []common.Hash{{}, {}},
+		logtest.Word(300), logtest.Word(-290), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(0))})
+	report = detector.AddTx([]*types.Log{logtest.Log(v3Pool, "Burn(address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.Word(5000), logtest.Word(1290), logtest.Word(1710))})
+	if report.JIT == nil || report.Err() == nil {
+		t.Errorf("AddTx() JIT report = %+v, want JIT liquidity", report)
+	}
+}
+
+func TestConflictGraph(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	buy := v2SwapLogs(pool, 100000, 0, 0, 90909, 1100000, 909091)
+	sell := v2SwapLogs(pool, 0, 90909, 104444, 0, 1045556, 960475)
+	bundles := [][][]*types.Log{
+		{buy, buy},
+		{sell},
+		{buy},
+	}
+
+	detector := NewDetector(Options{})
+	graph := detector.ConflictGraph(bundles)
+	if pools := graph.Conflicting(0, 1); len(pools) != 1 || pools[0] != pool {
+		t.Errorf("Conflicting(0, 1) = %v, want [%s]", pools, pool)
+	}
+	if pools := graph.Conflicting(1, 0); pools != nil {
+		t.Errorf("Conflicting(1, 0) = %v, want nil", pools)
+	}
+	if len(graph.Conflicts) != 1 {
+		t.Errorf("ConflictGraph() conflicts = %+v, want only 0 before 1", graph.Conflicts)
+	}
+
+	// No pair of {buy}, {buy}, {sell} conflicts, but all three in that order do.
+	third := graph.Summaries[2]
+	if pools := third.Then(third).Then(graph.Summaries[1]).SandwichedPools(); len(pools) != 1 {
+		t.Errorf("SandwichedPools() of three bundles = %v, want [%s]", pools, pool)
+	}
+	merged := append(append(append([][]*types.Log{}, bundles[2]...), bundles[2]...), bundles[1]...)
+	if err := detector.DetectSandwichForBundle(merged); err == nil {
+		t.Error("DetectSandwichForBundle() merged bundles error = nil, want sandwich")
+	}
+}
+
+// v2SwapLogs returns the Sync and Swap logs a V2 pair emits for one swap.
+func v2SwapLogs(pool common.Address, amount0In, amount1In, amount0Out, amount1Out, reserve0, reserve1 int64) []*types.Log {
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	return []*types.Log{
+		logtest.V2Sync(pool, reserve0, reserve1),
+		logtest.V2Swap(pool, trader, amount0In, amount1In, amount0Out, amount1Out),
+	}
+}
+
 var (
 	testCase0 = [][]*types.Log{
 		// tx1
@@ -327,38 +1044,38 @@ var (
 				},
 				Data: hexutil.MustDecode("0xffffffffffffffffffffffffffffffffffffffffffffe76eefee5f2095db0800000000000000000000000000000000000000000000000063030b852e1e7ba6300000000000000000000000000000000000000000201e4fb7bb2dc5c7940e7e13000000000000000000000000000000000000000000392bbff455b2bb9dafab33ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff5dd2000000000000000000000000000000000000000000000000000000000000000a"),
 			}, {
-			Address: common.HexToAddress("0xcF59B8C8BAA2dea520e3D549F97d4e49aDE17057"),
-			Topics: []common.Hash{
-				common.HexToHash("0x19b47279256b2a23a1665c810c8d55a1758940ee09377d4f8d26497a3577dc83"),
-				common.HexToHash("0x0000000000000000000000007a7ad9aa93cd0a2d0255326e5fb145cec14997ff"),
-				common.HexToHash("0x000000000000000000000000927c4306a908362694eece7e4f45c1624dd51721"),
-			},
-			Data: hexutil.MustDecode("0x000000000000000000000000000000000000000000000a0235f4380f2b3e7800ffffffffffffffffffffffffffffffffffffffffffffffffffdf98888f57ad3a0000000000000000000000000000000000000000001a8895ed39cbb97d40ba1b000000000000000000000000000000000000000000000006e27bd7319038a46bfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd9da900000000000000000000000000000000000000000000000015a543308721dedd0000000000000000000000000000000000000000000000000000000000000000"),
-		}, {
-			Address: common.HexToAddress("0x81C7294b66955824BC04acB642ae8dC58e6cE507"),
-			Topics: []common.Hash{
-				common.HexToHash("0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"),
-				common.HexToHash("0x0000000000000000000000007a7ad9aa93cd0a2d0255326e5fb145cec14997ff"),
-				common.HexToHash("0x000000000000000000000000927c4306a908362694eece7e4f45c1624dd51721"),
-			},
-			Data: hexutil.MustDecode("0x0000000000000000000000000000000000000000000009194852618227501000ffffffffffffffffffffffffffffffffffffffffffffffdb7dc39bf9b68a3d1400000000000000000000000000000000000000001ec15609a9c3c315b47cc4390000000000000000000000000000000000000000000002181e7c4540269d76a3ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff5a6f"),
-		}, {
-			Address: common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
-			Topics: []common.Hash{
-				common.HexToHash("0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f"),
-				common.HexToHash("0xb2008c0126f1adeed67d42da44ac4b5c5abd9ef156bd93c106e082cb10e04552"),
-				common.HexToHash("0x000000000000000000000000c0fab674ff7ddf8b891495ba9975b0fe1dcac735"),
-			},
-			Data: hexutil.MustDecode("0xffffffffffffffffffffffffffffffffffffffffffffff712f87ca2fadd49f5f0000000000000000000000000000000000000000000000023f8adb35e075df3900000000000000000000000000000000000000000000000000000001000276a40000000000000000000000000000000000000000000000000000000000000000fffffffffffffffffffffffffffffffffffffffffffffffffffffffffff276180000000000000000000000000000000000000000000000000000000000000008"),
-		}, {
-			Address: common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
-			Topics: []common.Hash{
-				common.HexToHash("0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f"),
-				common.HexToHash("0xed461e6026d36fa5c289a288cc450fb7556ad2e2642fcda0d173a284140d96bd"),
-				common.HexToHash("0x000000000000000000000000c0fab674ff7ddf8b891495ba9975b0fe1dcac735"),
+				Address: common.HexToAddress("0xcF59B8C8BAA2dea520e3D549F97d4e49aDE17057"),
+				Topics: []common.Hash{
+					common.HexToHash("0x19b47279256b2a23a1665c810c8d55a1758940ee09377d4f8d26497a3577dc83"),
+					common.HexToHash("0x0000000000000000000000007a7ad9aa93cd0a2d0255326e5fb145cec14997ff"),
+					common.HexToHash("0x000000000000000000000000927c4306a908362694eece7e4f45c1624dd51721"),
+				},
+				Data: hexutil.MustDecode("0x000000000000000000000000000000000000000000000a0235f4380f2b3e7800ffffffffffffffffffffffffffffffffffffffffffffffffffdf98888f57ad3a0000000000000000000000000000000000000000001a8895ed39cbb97d40ba1b000000000000000000000000000000000000000000000006e27bd7319038a46bfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd9da900000000000000000000000000000000000000000000000015a543308721dedd0000000000000000000000000000000000000000000000000000000000000000"),
+			}, {
+				Address: common.HexToAddress("0x81C7294b66955824BC04acB642ae8dC58e6cE507"),
+				Topics: []common.Hash{
+					common.HexToHash("0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"),
+					common.HexToHash("0x0000000000000000000000007a7ad9aa93cd0a2d0255326e5fb145cec14997ff"),
+					common.HexToHash("0x000000000000000000000000927c4306a908362694eece7e4f45c1624dd51721"),
+				},
+				Data: hexutil.MustDecode("0x0000000000000000000000000000000000000000000009194852618227501000ffffffffffffffffffffffffffffffffffffffffffffffdb7dc39bf9b68a3d1400000000000000000000000000000000000000001ec15609a9c3c315b47cc4390000000000000000000000000000000000000000000002181e7c4540269d76a3ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff5a6f"),
+			}, {
+				Address: common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
+				Topics: []common.Hash{
+					common.HexToHash("0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f"),
+					common.HexToHash("0xb2008c0126f1adeed67d42da44ac4b5c5abd9ef156bd93c106e082cb10e04552"),
+					common.HexToHash("0x000000000000000000000000c0fab674ff7ddf8b891495ba9975b0fe1dcac735"),
+				},
+				Data: hexutil.MustDecode("0xffffffffffffffffffffffffffffffffffffffffffffff712f87ca2fadd49f5f0000000000000000000000000000000000000000000000023f8adb35e075df3900000000000000000000000000000000000000000000000000000001000276a40000000000000000000000000000000000000000000000000000000000000000fffffffffffffffffffffffffffffffffffffffffffffffffffffffffff276180000000000000000000000000000000000000000000000000000000000000008"),
+			}, {
+				Address: common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
+				Topics: []common.Hash{
+					common.HexToHash("0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f"),
+					common.HexToHash("0xed461e6026d36fa5c289a288cc450fb7556ad2e2642fcda0d173a284140d96bd"),
+					common.HexToHash("0x000000000000000000000000c0fab674ff7ddf8b891495ba9975b0fe1dcac735"),
+				},
+				Data: hexutil.MustDecode("0xffffffffffffffffffffffffffffffffffffffffffffff17125e2972fc1198000000000000000000000000000000000000000000000000005a740cfe34ef868d000000000000000000000000000000000000000000006c756dcfcc1d9835b57e00000000000000000000000000000000000000000000000000592f0d2b1cd66dfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc5a6c0000000000000000000000000000000000000000000000000000000000000005"),
 			},
-			Data: hexutil.MustDecode("0xffffffffffffffffffffffffffffffffffffffffffffff17125e2972fc1198000000000000000000000000000000000000000000000000005a740cfe34ef868d000000000000000000000000000000000000000000006c756dcfcc1d9835b57e00000000000000000000000000000000000000000000000000592f0d2b1cd66dfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc5a6c0000000000000000000000000000000000000000000000000000000000000005"),
-		},
 		},
 		// tx3
 		{
@@ -0,0 +1,391 @@
+package bscexorcist
+
+import (
+	"fmt"
+
+	"github.com/48Club/bscexorcist/protocols"
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// positionKey identifies the liquidity position changed by a deposit or withdrawal. Two
+// events have the same key exactly when sameLiquidityOwner pairs them.
+type positionKey struct {
+	id    common.Hash
+	owner common.Address // owner is only set for fungible V2 liquidity, whose position id is zero
+}
+
+func liquidityPosition(event protocols.LiquidityEvent) positionKey {
+	if event.PositionID() != (common.Hash{}) {
+		return positionKey{id: event.PositionID()}
+	}
+	return positionKey{owner: protocols.LiquidityOwner(event)}
+}
+
+// pendingDeposit is a liquidity deposit together with the swap leg before it on the pool,
+// the front-run of a possible add-liquidity sandwich.
+type pendingDeposit struct {
+	deposit liquidityLeg
+	front   poolLeg
+}
+
+// poolSummary is what the IncrementalDetector keeps about the legs of a pool seen so far.
+type poolSummary struct {
+	// run holds the latest swap legs, all in one direction: a front-run candidate followed
+	// by its victims. A leg in the other direction completes a sandwich if the run holds
+	// two legs or more, and starts a new run.
+	run []poolLeg
+	// lastSwap is the latest swap leg, nil if the pool was not swapped yet.
+	lastSwap *poolLeg
+	// deposits and withdrawals hold the earliest deposit and withdrawal of each position
+	// not reversed since: the first leg of a JIT or liquidity-removal pattern.
+	deposits    map[positionKey]liquidityLeg
+	withdrawals map[positionKey]liquidityLeg
+	// pending is the latest deposit made after a swap leg.
+	pending *pendingDeposit
+}
+
+// revision marks the state at a Snapshot.
+type revision struct {
+	journalLen int
+	txs        int
+	spoofed    int
+	unverified int
+	backrun    bool
+	report     *Report
+}
+
+// IncrementalDetector checks a sequence of transactions as it grows, for builders adding
+// transactions to a block one at a time. It keeps a summary per pool and checks each new
+// swap leg and liquidity event against it in constant time, amortized over the legs of the
+// pool, and scores only the transactions of a completed pattern, with the signals Analyze uses.
+//
+// The patterns are those of Analyze, restricted so that they can be completed from the
+// summary: the front-run and victims of a sandwich are the consecutive legs in one direction
+// right before the back-run, and the victim of a JIT or liquidity-removal pattern is the
+// last swap before the attacker's second transaction.
+type IncrementalDetector struct {
+	detector   *Detector
+	pools      map[common.Address]*poolSummary
+	txLogs     [][]*types.Log
+	txPools    [][]common.Address // txPools holds the pools swapped by each transaction
+	classes    []protocols.TxClass
+	spoofed    []FlaggedPool
+	unverified []FlaggedPool
+	backrun    bool // backrun is true while the sequence is a backrun bundle, see isBackrunBundle
+	report     *Report
+	journal    []func()
+	revisions  []revision
+}
+
+// NewIncrementalDetector creates an empty IncrementalDetector analyzing transactions with the given options.
+func NewIncrementalDetector(opts Options) *IncrementalDetector {
+	return &IncrementalDetector{
+		detector: NewDetector(opts),
+		pools:    make(map[common.Address]*poolSummary),
+		report:   &Report{},
+	}
+}
+
+// AddTx appends a transaction, given its logs, and returns the report of the sequence.
+// Once an attack is found its report is returned until reverted. Until then the report
+// holds the patterns completed by the latest transaction, scored clean.
+func (d *IncrementalDetector) AddTx(txLogs []*types.Log) *Report {
+	txIndex := len(d.txLogs)
+	d.txLogs = append(d.txLogs, txLogs)
+
+	opts := d.detector.opts
+	if opts.V4Pools != nil {
+		protocols.ObservePoolKeys(opts.V4Pools, txLogs)
+	}
+	swaps, spoofed, unverified := d.detector.txSwaps(txLogs)
+	for _, pool := range spoofed {
+		d.spoofed = append(d.spoofed, FlaggedPool{TxIndex: txIndex, Pool: pool})
+	}
+	for _, pool := range unverified {
+		d.unverified = append(d.unverified, FlaggedPool{TxIndex: txIndex, Pool: pool})
+	}
+
+	class := d.detector.config.ClassifyTransaction(txLogs, swaps, opts.V4Pools)
+	d.classes = append(d.classes, class)
+	if txIndex == 0 {
+		d.backrun = class == protocols.TxSingleSwap || class == protocols.TxMultiHop
+	} else {
+		d.backrun = d.backrun && class == protocols.TxCyclicArbitrage
+	}
+
+	legs := effectiveLegs(swaps)
+	pools := make([]common.Address, len(legs))
+	for i, swap := range legs {
+		pools[i] = swap.PairID()
+	}
+	d.txPools = append(d.txPools, pools)
+
+	report := &Report{
+		SpoofedPools:    d.spoofed[:len(d.spoofed):len(d.spoofed)],
+		UnverifiedPools: d.unverified[:len(d.unverified):len(d.unverified)],
+		Classes:         d.classes[:len(d.classes):len(d.classes)],
+	}
+	// Liquidity events go first, so that a swap is never the victim of a liquidity change
+	// of its own transaction.
+	for _, event := range protocols.ParseLiquidityEvents(txLogs) {
+		d.addLiquidityLeg(report, liquidityLeg{txIndex: txIndex, event: event})
+	}
+	for _, swap := range legs {
+		d.addSwapLeg(report, poolLeg{txIndex: txIndex, swap: swap})
+	}
+
+	if d.report.Err() == nil {
+		d.report = report
+	}
+	return d.report
+}
+
+// addSwapLeg checks whether a swap leg completes a sandwich or an add-liquidity sandwich
+// on its pool, and records it.
+func (d *IncrementalDetector) addSwapLeg(report *Report, leg poolLeg) {
+	pool := leg.swap.PairID()
+	summary := d.summary(pool)
+
+	run := summary.run
+	if len(run) > 0 && run[0].swap.IsToken0To1() != leg.swap.IsToken0To1() {
+		if len(run) >= 2 && !d.backrun {
+			d.completeSandwich(report, pool, run, leg)
+		}
+		run = []poolLeg{leg}
+	} else {
+		run = append(run, leg)
+	}
+	journalSet(d, &summary.run, run)
+
+	if pending := summary.pending; pending != nil && pending.front.swap.IsToken0To1() != leg.swap.IsToken0To1() {
+		d.completeAddLiquidity(report, pool, pending, leg)
+	}
+	journalSet(d, &summary.lastSwap, &leg)
+}
+
+// addLiquidityLeg checks whether a liquidity event completes a JIT or liquidity-removal
+// pattern on its pool, and records it.
+func (d *IncrementalDetector) addLiquidityLeg(report *Report, leg liquidityLeg) {
+	pool := leg.event.PairID()
+	summary := d.summary(pool)
+	if summary.deposits == nil {
+		journalSet(d, &summary.deposits, make(map[positionKey]liquidityLeg))
+		journalSet(d, &summary.withdrawals, make(map[positionKey]liquidityLeg))
+	}
+
+	opened, reversed := summary.withdrawals, summary.deposits
+	if leg.event.IsAdd() {
+		opened, reversed = summary.deposits, summary.withdrawals
+	}
+	key := liquidityPosition(leg.event)
+	if first, ok := reversed[key]; ok {
+		// The last swap came from a transaction between the two liquidity changes.
+		if victim := summary.lastSwap; victim != nil && victim.txIndex > first.txIndex {
+			if leg.event.IsAdd() {
+				d.completeLiquidityRemoval(report, pool, first, *victim, leg)
+			} else {
+				d.completeJIT(report, pool, first, *victim, leg)
+			}
+		}
+		journalDelete(d, reversed, key)
+	}
+	if _, ok := opened[key]; !ok {
+		journalPut(d, opened, key, leg)
+	}
+
+	if leg.event.IsAdd() && summary.lastSwap != nil {
+		journalSet(d, &summary.pending, &pendingDeposit{deposit: leg, front: *summary.lastSwap})
+	}
+}
+
+// completeSandwich scores the sandwich of the run of legs ended by back.
+func (d *IncrementalDetector) completeSandwich(report *Report, pool common.Address, run []poolLeg, back poolLeg) {
+	front := run[0]
+	sandwichErr := &SandwichError{
+		Pool:      pool,
+		FrontTx:   front.txIndex,
+		VictimTx:  run[1].txIndex,
+		BackTx:    back.txIndex,
+		Heuristic: protocols.IsHeuristic(back.swap),
+	}
+	sandwichErr.Price = priceEvidence(d.reserves(front.txIndex, run[1].txIndex), pool, sandwichErr.FrontTx, sandwichErr.VictimTx, run[1].swap.IsToken0To1())
+	txs := []int{front.txIndex, back.txIndex}
+	for _, leg := range run[1:] {
+		sandwichErr.Victims = append(sandwichErr.Victims, d.detector.victim(d.txLogs, pool, leg))
+		txs = append(txs, leg.txIndex)
+	}
+	sandwichErr.VictimRoute = sandwichErr.Victims[0].Route
+	for _, leg := range run {
+		if protocols.IsHeuristic(leg.swap) {
+			sandwichErr.Heuristic = true
+		}
+	}
+
+	sandwichErr.Score = d.detector.scoreSandwich(d.txLogs, sandwichErr, []poolLeg{front, back}, d.involvedPools(pool, txs...))
+	if report.Sandwich == nil || sandwichErr.Score.Total > report.Sandwich.Score.Total {
+		report.Sandwich = sandwichErr
+	}
+	report.raiseVerdict(sandwichErr.Score)
+}
+
+// completeJIT scores the JIT pattern of a deposit withdrawn after the victim swap.
+func (d *IncrementalDetector) completeJIT(report *Report, pool common.Address, mint liquidityLeg, victim poolLeg, burn liquidityLeg) {
+	fee0, fee1 := protocols.CollectedFees(burn.event)
+	jit := &JITLiquidityError{
+		Pool:     pool,
+		Provider: protocols.LiquidityOwner(mint.event),
+		MintTx:   mint.txIndex,
+		VictimTx: victim.txIndex,
+		BurnTx:   burn.txIndex,
+		Fee0:     fee0,
+		Fee1:     fee1,
+	}
+	samePosition := burn.event.PositionID() != (common.Hash{})
+	jit.Score = d.detector.scoreLiquidity(d.txLogs, d.involvedPools(pool, mint.txIndex, victim.txIndex, burn.txIndex), jit.MintTx, jit.VictimTx, jit.BurnTx, samePosition)
+	if report.JIT == nil || jit.Score.Total > report.JIT.Score.Total {
+		report.JIT = jit
+	}
+	report.raiseVerdict(jit.Score)
+}
+
+// completeLiquidityRemoval scores the liquidity-removal pattern of a withdrawal deposited
+// again after the victim swap.
+func (d *IncrementalDetector) completeLiquidityRemoval(report *Report, pool common.Address, remove liquidityLeg, victim poolLeg, add liquidityLeg) {
+	removal := &LiquidityRemovalError{
+		Pool:          pool,
+		Provider:      protocols.LiquidityOwner(remove.event),
+		RemoveTx:      remove.txIndex,
+		VictimTx:      victim.txIndex,
+		AddTx:         add.txIndex,
+		ExtraSlippage: extraSlippage(d.reserves(victim.txIndex), pool, victim.txIndex, remove),
+	}
+	samePosition := remove.event.PositionID() != (common.Hash{})
+	removal.Score = d.detector.scoreLiquidity(d.txLogs, d.involvedPools(pool, remove.txIndex, victim.txIndex, add.txIndex), removal.RemoveTx, removal.VictimTx, removal.AddTx, samePosition)
+	if report.LiquidityRemoval == nil || removal.Score.Total > report.LiquidityRemoval.Score.Total {
+		report.LiquidityRemoval = removal
+	}
+	report.raiseVerdict(removal.Score)
+}
+
+// completeAddLiquidity scores the add-liquidity sandwich of a pending deposit ended by back,
+// if the front-run and back-run share a trader.
+func (d *IncrementalDetector) completeAddLiquidity(report *Report, pool common.Address, pending *pendingDeposit, back poolLeg) {
+	front, deposit := pending.front, pending.deposit
+	pools := d.involvedPools(pool, front.txIndex, deposit.txIndex, back.txIndex)
+	if !sharedIdentity(d.txLogs, pools, front.txIndex, back.txIndex, deposit.txIndex) {
+		return
+	}
+
+	add := &AddLiquiditySandwichError{
+		Pool:      pool,
+		Provider:  protocols.LiquidityOwner(deposit.event),
+		FrontTx:   front.txIndex,
+		VictimTx:  deposit.txIndex,
+		BackTx:    back.txIndex,
+		ValueLost: depositValueLost(d.reserves(front.txIndex), pool, front.txIndex, deposit),
+	}
+	add.Score = d.detector.scoreLiquidity(d.txLogs, pools, add.FrontTx, add.VictimTx, add.BackTx, false)
+	if report.AddLiquidity == nil || add.Score.Total > report.AddLiquidity.Score.Total {
+		report.AddLiquidity = add
+	}
+	report.raiseVerdict(add.Score)
+}
+
+// reserves returns the V2 reserves observed in the given transactions.
+func (d *IncrementalDetector) reserves(txs ...int) *uniswapv2.PoolState {
+	reserves := uniswapv2.NewPoolState()
+	for _, txIndex := range txs {
+		protocols.ObserveReserves(reserves, txIndex, d.txLogs[txIndex])
+	}
+	return reserves
+}
+
+// involvedPools returns the pool and the pools swapped by the given transactions, the
+// pools sharedIdentity must not take for an attacker.
+func (d *IncrementalDetector) involvedPools(pool common.Address, txs ...int) []common.Address {
+	pools := []common.Address{pool}
+	for _, txIndex := range txs {
+		pools = append(pools, d.txPools[txIndex]...)
+	}
+	return pools
+}
+
+// summary returns the summary of a pool, creating it if needed.
+func (d *IncrementalDetector) summary(pool common.Address) *poolSummary {
+	summary, ok := d.pools[pool]
+	if !ok {
+		summary = &poolSummary{}
+		d.pools[pool] = summary
+		d.journal = append(d.journal, func() { delete(d.pools, pool) })
+	}
+	return summary
+}
+
+// journalSet sets *field to value, journaling the previous value for Revert.
+func journalSet[T any](d *IncrementalDetector, field *T, value T) {
+	prev := *field
+	d.journal = append(d.journal, func() { *field = prev })
+	*field = value
+}
+
+// journalPut sets m[key] to value, which must be absent, journaling its removal for Revert.
+func journalPut(d *IncrementalDetector, m map[positionKey]liquidityLeg, key positionKey, value liquidityLeg) {
+	m[key] = value
+	d.journal = append(d.journal, func() { delete(m, key) })
+}
+
+// journalDelete deletes m[key], journaling its value for Revert.
+func journalDelete(d *IncrementalDetector, m map[positionKey]liquidityLeg, key positionKey) {
+	prev := m[key]
+	delete(m, key)
+	d.journal = append(d.journal, func() { m[key] = prev })
+}
+
+// Err returns the attack found in the sequence so far, or nil.
+func (d *IncrementalDetector) Err() error {
+	return d.report.Err()
+}
+
+// Len returns the number of transactions added.
+func (d *IncrementalDetector) Len() int {
+	return len(d.txLogs)
+}
+
+// Snapshot returns an identifier for the current state, to be passed to Revert.
+func (d *IncrementalDetector) Snapshot() int {
+	d.revisions = append(d.revisions, revision{
+		journalLen: len(d.journal),
+		txs:        len(d.txLogs),
+		spoofed:    len(d.spoofed),
+		unverified: len(d.unverified),
+		backrun:    d.backrun,
+		report:     d.report,
+	})
+	return len(d.revisions) - 1
+}
+
+// Revert undoes the transactions added since the snapshot with the given identifier was
+// taken. The snapshot and all later ones become invalid. Returns an error and leaves the
+// state unchanged if the snapshot is unknown or already invalid.
+func (d *IncrementalDetector) Revert(id int) error {
+	if id < 0 || id >= len(d.revisions) {
+		return fmt.Errorf("snapshot %d: unknown or reverted", id)
+	}
+	rev := d.revisions[id]
+
+	for i := len(d.journal) - 1; i >= rev.journalLen; i-- {
+		d.journal[i]()
+	}
+	d.journal = d.journal[:rev.journalLen]
+	d.txLogs = d.txLogs[:rev.txs]
+	d.txPools = d.txPools[:rev.txs]
+	d.classes = d.classes[:rev.txs]
+	d.spoofed = d.spoofed[:rev.spoofed]
+	d.unverified = d.unverified[:rev.unverified]
+	d.backrun = rev.backrun
+	d.report = rev.report
+	d.revisions = d.revisions[:id]
+	return nil
+}
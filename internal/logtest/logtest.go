@@ -0,0 +1,79 @@
+// Package logtest builds event logs for the tests of the detector and protocol packages.
+package logtest
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Topic returns the topic of a canonical event signature, e.g. "Sync(uint112,uint112)".
+func Topic(event string) common.Hash {
+	return crypto.Keccak256Hash([]byte(event))
+}
+
+// Word returns v as a 32-byte ABI word, in two's complement if negative.
+func Word(v int64) []byte {
+	return BigWord(big.NewInt(v))
+}
+
+// BigWord returns v as a 32-byte ABI word, in two's complement if negative.
+func BigWord(v *big.Int) []byte {
+	return math.U256Bytes(new(big.Int).Set(v))
+}
+
+// AddressWord returns an address as a 32-byte ABI word.
+func AddressWord(address common.Address) []byte {
+	return common.LeftPadBytes(address.Bytes(), 32)
+}
+
+// Bool returns a bool as a 32-byte ABI word.
+func Bool(v bool) []byte {
+	if v {
+		return Word(1)
+	}
+	return Word(0)
+}
+
+// IntTopic returns v as an indexed topic, in two's complement if negative.
+func IntTopic(v int64) common.Hash {
+	return common.BytesToHash(Word(v))
+}
+
+// AddressTopic returns an address as an indexed topic.
+func AddressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+// Log returns a log of the given event emitted by address, with the indexed topics
+// following the event topic and the words concatenated as data.
+func Log(address common.Address, event string, indexed []common.Hash, words ...[]byte) *types.Log {
+	log := &types.Log{
+		Address: address,
+		Topics:  append([]common.Hash{Topic(event)}, indexed...),
+	}
+	for _, word := range words {
+		log.Data = append(log.Data, word...)
+	}
+	return log
+}
+
+// Transfer returns an ERC20 Transfer log.
+func Transfer(token, from, to common.Address, value int64) *types.Log {
+	return Log(token, "Transfer(address,address,uint256)", []common.Hash{AddressTopic(from), AddressTopic(to)}, Word(value))
+}
+
+// V2Sync returns a Uniswap V2 Sync log.
+func V2Sync(pool common.Address, reserve0, reserve1 int64) *types.Log {
+	return Log(pool, "Sync(uint112,uint112)", nil, Word(reserve0), Word(reserve1))
+}
+
+// V2Swap returns a Uniswap V2 Swap log sent and received by trader.
+func V2Swap(pool, trader common.Address, amount0In, amount1In, amount0Out, amount1Out int64) *types.Log {
+	return Log(pool, "Swap(address,uint256,uint256,uint256,uint256,address)",
+		[]common.Hash{AddressTopic(trader), AddressTopic(trader)},
+		Word(amount0In), Word(amount1In), Word(amount0Out), Word(amount1Out))
+}
@@ -0,0 +1,82 @@
+package bscexorcist
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// JITLiquidityError is returned when liquidity is added to a position right before a victim
+// swap and removed from it right after, capturing the swap's LP fees.
+// Transactions are identified by their index in the bundle.
+type JITLiquidityError struct {
+	Pool common.Address
+	// Provider is the owner of the position, the NFT holder for positions held through a
+	// position manager whose NFT was minted in MintTx.
+	Provider common.Address
+	MintTx   int
+	VictimTx int
+	BurnTx   int
+	// Fee0 and Fee1 are the fees collected with the withdrawal, nil if no V3 Collect
+	// event reported them.
+	Fee0 *big.Int
+	Fee1 *big.Int
+	// Score weighs the evidence that the pattern is an attack.
+	Score *Score
+}
+
+// Error implements the error interface.
+func (e *JITLiquidityError) Error() string {
+	msg := fmt.Sprintf("JIT liquidity attack detected on pool: %s by provider %s", e.Pool.Hex(), e.Provider.Hex())
+	if e.Fee0 != nil {
+		msg += fmt.Sprintf(" (fees captured: %s token0, %s token1)", e.Fee0, e.Fee1)
+	}
+	if e.Score != nil {
+		msg += fmt.Sprintf("; verdict %s (score %g)", e.Score.Verdict, e.Score.Total)
+	}
+	return msg
+}
+
+// liquidityLeg is a liquidity event on a pool together with the index of the transaction it belongs to.
+type liquidityLeg struct {
+	txIndex int
+	event   protocols.LiquidityEvent
+}
+
+// findJIT returns the first position that was added before and removed after a swap
+// of another transaction on the same pool by the same owner. Positions held through a
+// position manager are matched by NFT when the manager's events name it; fungible V2
+// liquidity is matched by owner alone.
+func findJIT(pools []common.Address, poolLegs map[common.Address][]poolLeg, liquidityLegs map[common.Address][]liquidityLeg) *JITLiquidityError {
+	for _, pool := range pools {
+		legs := liquidityLegs[pool]
+		for i, mint := range legs {
+			if !mint.event.IsAdd() {
+				continue
+			}
+			for _, burn := range legs[i+1:] {
+				if burn.event.IsAdd() || burn.txIndex <= mint.txIndex+1 || !sameLiquidityOwner(burn.event, mint.event) {
+					continue
+				}
+				for _, swap := range poolLegs[pool] {
+					if swap.txIndex <= mint.txIndex || swap.txIndex >= burn.txIndex {
+						continue
+					}
+					fee0, fee1 := protocols.CollectedFees(burn.event)
+					return &JITLiquidityError{
+						Pool:     pool,
+						Provider: protocols.LiquidityOwner(mint.event),
+						MintTx:   mint.txIndex,
+						VictimTx: swap.txIndex,
+						BurnTx:   burn.txIndex,
+						Fee0:     fee0,
+						Fee1:     fee1,
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package aggregator provides parsing of the summary events emitted by aggregator
+// routers, which describe a user's end-to-end trade across all pools it was routed through.
+package aggregator
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// Swapped(sender, srcToken, dstToken, dstReceiver, spentAmount, returnAmount),
+	// emitted by 1inch AggregationRouter and KyberSwap MetaAggregationRouter.
+	swappedSignature = common.HexToHash("0xd6d4f5681c246c9f42c203e287975af1601f8df8035a9251f79aab5c8f09e2f8")
+
+	// Swapped(sender indexed, srcToken indexed, dstToken indexed, dstReceiver, amount, spentAmount,
+	// returnAmount, minReturnAmount, guaranteedAmount, referrer), emitted by OpenOcean.
+	openOceanSwappedSignature = common.HexToHash("0x76af224a143865a50b41496e1a73622698692c565c1214bc862f18e22d829c5e")
+)
+
+// Route is the end-to-end trade reported by an aggregator router.
+// Native BNB is reported as the router's placeholder (see tools.NormalizeToken).
+type Route struct {
+	Router       common.Address
+	Sender       common.Address
+	Receiver     common.Address
+	SrcToken     common.Address
+	DstToken     common.Address
+	SpentAmount  *big.Int
+	ReturnAmount *big.Int
+	// MinReturnAmount is the slippage limit set by the user, nil if the router does not report it.
+	MinReturnAmount *big.Int
+}
+
+// RealizedRate returns the amount of DstToken received per unit of SrcToken spent,
+// in raw token units. Returns nil if nothing was spent.
+func (r *Route) RealizedRate() *big.Float {
+	if r.SpentAmount.Sign() == 0 {
+		return nil
+	}
+	return new(big.Float).Quo(new(big.Float).SetInt(r.ReturnAmount), new(big.Float).SetInt(r.SpentAmount))
+}
+
+// ParseRoute parses an aggregator summary log into a Route struct.
+// Returns nil if the log is not a recognized route event.
+func ParseRoute(log *types.Log) *Route {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+
+	switch log.Topics[0] {
+	case swappedSignature:
+		// Data layout: sender, srcToken, dstToken, dstReceiver, spentAmount, returnAmount
+		if len(log.Topics) != 1 || len(log.Data) < 192 {
+			return nil
+		}
+		return &Route{
+			Router:       log.Address,
+			Sender:       common.BytesToAddress(log.Data[:32]),
+			SrcToken:     common.BytesToAddress(log.Data[32:64]),
+			DstToken:     common.BytesToAddress(log.Data[64:96]),
+			Receiver:     common.BytesToAddress(log.Data[96:128]),
+			SpentAmount:  new(big.Int).SetBytes(log.Data[128:160]),
+			ReturnAmount: new(big.Int).SetBytes(log.Data[160:192]),
+		}
+	case openOceanSwappedSignature:
+		// Topics: signature, sender, srcToken, dstToken
+		// Data layout: dstReceiver, amount, spentAmount, returnAmount, minReturnAmount, guaranteedAmount, referrer
+		if len(log.Topics) != 4 || len(log.Data) < 224 {
+			return nil
+		}
+		return &Route{
+			Router:          log.Address,
+			Sender:          common.BytesToAddress(log.Topics[1].Bytes()),
+			SrcToken:        common.BytesToAddress(log.Topics[2].Bytes()),
+			DstToken:        common.BytesToAddress(log.Topics[3].Bytes()),
+			Receiver:        common.BytesToAddress(log.Data[:32]),
+			SpentAmount:     new(big.Int).SetBytes(log.Data[64:96]),
+			ReturnAmount:    new(big.Int).SetBytes(log.Data[96:128]),
+			MinReturnAmount: new(big.Int).SetBytes(log.Data[128:160]),
+		}
+	}
+	return nil
+}
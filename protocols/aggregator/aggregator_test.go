@@ -0,0 +1,51 @@
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	usdt = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	user = common.HexToAddress("0xc7e53484f23333b722d47cc58e93f86806db6220")
+)
+
+func TestParseRoute(t *testing.T) {
+	router := common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582")
+	log := logtest.Log(router, "Swapped(address,address,address,address,uint256,uint256)", nil,
+		logtest.AddressWord(user), logtest.AddressWord(tools.NativeTokenPlaceholder), logtest.AddressWord(usdt),
+		logtest.AddressWord(user), logtest.Word(2), logtest.Word(1180))
+
+	route := ParseRoute(log)
+	if route == nil {
+		t.Fatal("ParseRoute() = nil")
+	}
+	if route.Router != router || route.Sender != user || route.Receiver != user || route.SrcToken != tools.NativeTokenPlaceholder || route.DstToken != usdt {
+		t.Errorf("ParseRoute() = %+v", route)
+	}
+	if route.RealizedRate().Text('f', 0) != "590" || route.MinReturnAmount != nil {
+		t.Errorf("ParseRoute() rate = %v, min return = %v, want 590 and nil", route.RealizedRate(), route.MinReturnAmount)
+	}
+}
+
+func TestParseRouteOpenOcean(t *testing.T) {
+	router := common.HexToAddress("0x6352a56caadC4F1E25CD6c75970Fa768A3304e64")
+	wbnb := tools.WBNB
+	log := logtest.Log(router, "Swapped(address,address,address,address,uint256,uint256,uint256,uint256,uint256,address)",
+		[]common.Hash{logtest.AddressTopic(user), logtest.AddressTopic(usdt), logtest.AddressTopic(wbnb)},
+		logtest.AddressWord(user), logtest.Word(1200), logtest.Word(1200), logtest.Word(2), logtest.Word(1), logtest.Word(2), logtest.AddressWord(common.Address{}))
+
+	route := ParseRoute(log)
+	if route == nil {
+		t.Fatal("ParseRoute() = nil")
+	}
+	if route.SrcToken != usdt || route.DstToken != wbnb || route.SpentAmount.Int64() != 1200 || route.ReturnAmount.Int64() != 2 {
+		t.Errorf("ParseRoute() = %+v", route)
+	}
+	if route.MinReturnAmount == nil || route.MinReturnAmount.Int64() != 1 {
+		t.Errorf("ParseRoute() min return = %v, want 1", route.MinReturnAmount)
+	}
+}
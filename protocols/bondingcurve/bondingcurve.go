@@ -0,0 +1,194 @@
+// Package bondingcurve provides swap event parsing for bonding-curve launchpads.
+//
+// Launchpads are described declaratively: each Launchpad entry names a buy or sell
+// event and where the token and amounts sit in its data, so a new launchpad can be
+// supported by adding data to a Registry instead of writing a parser.
+package bondingcurve
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Launchpad describes one buy or sell event of a bonding-curve launchpad.
+// Word indexes count 32-byte words in the log data, starting from 0.
+type Launchpad struct {
+	Name            string // Name identifies the launchpad, e.g. "fourmeme"
+	Event           string // Event is the canonical event signature, e.g. "TokenSale(address,address,uint256)"
+	Buy             bool   // Buy is true if the event buys tokens with the quote currency
+	TokenWord       int    // TokenWord is the data word holding the token address
+	TokenAmountWord int    // TokenAmountWord is the data word holding the token amount
+	QuoteAmountWord int    // QuoteAmountWord is the data word holding the quote (BNB) amount
+}
+
+// Topic returns the event topic of the launchpad event.
+func (l Launchpad) Topic() common.Hash {
+	return crypto.Keccak256Hash([]byte(l.Event))
+}
+
+// minDataLen returns the minimum data length needed to decode the event.
+func (l Launchpad) minDataLen() int {
+	words := l.TokenWord
+	if l.TokenAmountWord > words {
+		words = l.TokenAmountWord
+	}
+	if l.QuoteAmountWord > words {
+		words = l.QuoteAmountWord
+	}
+	return (words + 1) * 32
+}
+
+// FourMemeLaunchpads are the events of the FourMeme TokenManager (V1) and TokenManager2.
+var FourMemeLaunchpads = []Launchpad{
+	// FourMeme TokenManager2: (token, account, price, amount, cost, fee, offers, funds)
+	{Name: "fourmeme", Event: "TokenPurchase(address,address,uint256,uint256,uint256,uint256,uint256,uint256)", Buy: true, TokenWord: 0, TokenAmountWord: 3, QuoteAmountWord: 4},
+	{Name: "fourmeme", Event: "TokenSale(address,address,uint256,uint256,uint256,uint256,uint256,uint256)", Buy: false, TokenWord: 0, TokenAmountWord: 3, QuoteAmountWord: 4},
+	// FourMeme TokenManager (V1): (token, account, tokenAmount, etherAmount)
+	{Name: "fourmeme-v1", Event: "TokenPurchase(address,address,uint256,uint256)", Buy: true, TokenWord: 0, TokenAmountWord: 2, QuoteAmountWord: 3},
+	{Name: "fourmeme-v1", Event: "TokenSale(address,address,uint256,uint256)", Buy: false, TokenWord: 0, TokenAmountWord: 2, QuoteAmountWord: 3},
+}
+
+// FlapLaunchpads are the events of the Flap portal.
+var FlapLaunchpads = []Launchpad{
+	// Flap portal: (ts, token, trader, amount, eth, fee, postPrice)
+	{Name: "flap", Event: "TokenBought(uint256,address,address,uint256,uint256,uint256,uint256)", Buy: true, TokenWord: 1, TokenAmountWord: 3, QuoteAmountWord: 4},
+	{Name: "flap", Event: "TokenSold(uint256,address,address,uint256,uint256,uint256,uint256)", Buy: false, TokenWord: 1, TokenAmountWord: 3, QuoteAmountWord: 4},
+}
+
+// DefaultLaunchpads are the launchpad events recognized out of the box.
+var DefaultLaunchpads = append(append([]Launchpad{}, FourMemeLaunchpads...), FlapLaunchpads...)
+
+// defaultRegistry backs the package-level Lookup and ParseSwap, and nil registries.
+var defaultRegistry = MustNewRegistry(DefaultLaunchpads...)
+
+// Registry recognizes a fixed set of launchpad events by their topic.
+// A nil *Registry recognizes DefaultLaunchpads.
+type Registry struct {
+	launchpads map[common.Hash]Launchpad
+}
+
+// NewRegistry returns a registry of the given launchpad events. A later entry with the
+// same event replaces an earlier one.
+func NewRegistry(launchpads ...Launchpad) (*Registry, error) {
+	r := &Registry{launchpads: make(map[common.Hash]Launchpad, len(launchpads))}
+	for _, launchpad := range launchpads {
+		if launchpad.Event == "" {
+			return nil, fmt.Errorf("launchpad %q: missing event signature", launchpad.Name)
+		}
+		if launchpad.TokenWord < 0 || launchpad.TokenAmountWord < 0 || launchpad.QuoteAmountWord < 0 {
+			return nil, fmt.Errorf("launchpad %q: negative word index", launchpad.Name)
+		}
+		r.launchpads[launchpad.Topic()] = launchpad
+	}
+	return r, nil
+}
+
+// MustNewRegistry is like NewRegistry but panics if a launchpad is invalid.
+func MustNewRegistry(launchpads ...Launchpad) *Registry {
+	r, err := NewRegistry(launchpads...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Lookup returns the launchpad event registered for a topic.
+func (r *Registry) Lookup(topic common.Hash) (Launchpad, bool) {
+	if r == nil {
+		r = defaultRegistry
+	}
+	launchpad, ok := r.launchpads[topic]
+	return launchpad, ok
+}
+
+// Lookup returns the launchpad event of DefaultLaunchpads for a topic.
+func Lookup(topic common.Hash) (Launchpad, bool) {
+	return defaultRegistry.Lookup(topic)
+}
+
+// CurveSwap implements SwapEvent for bonding-curve launchpads.
+// Buying tokens with the quote currency is treated as token0 -> token1.
+type CurveSwap struct {
+	launchpad   string
+	token       common.Address
+	buySide     bool
+	tokenAmount *big.Int
+	quoteAmount *big.Int
+}
+
+// PairID returns the token address, as every token trades on its own curve.
+func (s *CurveSwap) PairID() common.Address {
+	return s.token
+}
+
+// IsToken0To1 returns true if the swap buys tokens from the curve.
+func (s *CurveSwap) IsToken0To1() bool {
+	return s.buySide
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *CurveSwap) AmountIn() *big.Int {
+	if s.buySide {
+		return new(big.Int).Set(s.quoteAmount)
+	}
+	return new(big.Int).Set(s.tokenAmount)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *CurveSwap) AmountOut() *big.Int {
+	if s.buySide {
+		return new(big.Int).Set(s.tokenAmount)
+	}
+	return new(big.Int).Set(s.quoteAmount)
+}
+
+// Launchpad returns the name of the launchpad the swap was executed on.
+func (s *CurveSwap) Launchpad() string {
+	return s.launchpad
+}
+
+// Token returns the token traded against the curve.
+func (s *CurveSwap) Token() common.Address {
+	return s.token
+}
+
+// QuoteToken returns the asset the token is priced in. Curves trade against native BNB,
+// reported as tools.NativeToken; tools.NormalizeToken maps it to WBNB to match WBNB-quoted pools.
+func (s *CurveSwap) QuoteToken() common.Address {
+	return tools.NativeToken
+}
+
+// ParseSwap parses a launchpad buy or sell log of DefaultLaunchpads into a CurveSwap struct.
+// Returns nil if the log is not a valid launchpad event.
+func ParseSwap(log *types.Log) *CurveSwap {
+	return defaultRegistry.ParseSwap(log)
+}
+
+// ParseSwap parses a buy or sell log of a registered launchpad into a CurveSwap struct.
+// Returns nil if the log is not a valid launchpad event.
+func (r *Registry) ParseSwap(log *types.Log) *CurveSwap {
+	if len(log.Topics) != 1 {
+		return nil
+	}
+	launchpad, ok := r.Lookup(log.Topics[0])
+	if !ok || len(log.Data) < launchpad.minDataLen() {
+		return nil
+	}
+
+	return &CurveSwap{
+		launchpad:   launchpad.Name,
+		token:       common.BytesToAddress(word(log.Data, launchpad.TokenWord)),
+		buySide:     launchpad.Buy,
+		tokenAmount: new(big.Int).SetBytes(word(log.Data, launchpad.TokenAmountWord)),
+		quoteAmount: new(big.Int).SetBytes(word(log.Data, launchpad.QuoteAmountWord)),
+	}
+}
+
+func word(data []byte, index int) []byte {
+	return data[index*32 : (index+1)*32]
+}
@@ -0,0 +1,73 @@
+package bondingcurve
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	manager := common.HexToAddress("0x5c952063c7fc8610FFDB798152D69F0B9550762b")
+	token := common.HexToAddress("0x4e3a5f2c1d9b7e8a6c0f1b2d3e4f5a6b7c8d4444")
+	account := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+
+	// FourMeme TokenManager2 purchase: (token, account, price, amount, cost, fee, offers, funds)
+	buy := logtest.Log(manager, "TokenPurchase(address,address,uint256,uint256,uint256,uint256,uint256,uint256)", nil,
+		logtest.AddressWord(token), logtest.AddressWord(account), logtest.Word(5), logtest.Word(1000000), logtest.Word(200),
+		logtest.Word(2), logtest.Word(0), logtest.Word(0))
+	swap := ParseSwap(buy)
+	if swap == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+	if swap.Launchpad() != "fourmeme" || swap.PairID() != token || swap.Token() != token || swap.QuoteToken() != tools.NativeToken {
+		t.Errorf("ParseSwap() = launchpad %q, pair %s, quote %s", swap.Launchpad(), swap.PairID().Hex(), swap.QuoteToken().Hex())
+	}
+	if !swap.IsToken0To1() || swap.AmountIn().Int64() != 200 || swap.AmountOut().Int64() != 1000000 {
+		t.Errorf("ParseSwap() = buy %v, %v -> %v, want buy of 1000000 for 200", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+
+	// Flap sale: (ts, token, trader, amount, eth, fee, postPrice)
+	sell := logtest.Log(manager, "TokenSold(uint256,address,address,uint256,uint256,uint256,uint256)", nil,
+		logtest.Word(1700000000), logtest.AddressWord(token), logtest.AddressWord(account), logtest.Word(1000000), logtest.Word(190),
+		logtest.Word(2), logtest.Word(5))
+	if swap := ParseSwap(sell); swap == nil || swap.Launchpad() != "flap" || swap.IsToken0To1() ||
+		swap.AmountIn().Int64() != 1000000 || swap.AmountOut().Int64() != 190 {
+		t.Errorf("ParseSwap() flap sale = %+v, want sale of 1000000 for 190", swap)
+	}
+
+	buy.Data = buy.Data[:128]
+	if swap := ParseSwap(buy); swap != nil {
+		t.Errorf("ParseSwap() truncated log = %+v, want nil", swap)
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	launchpad := Launchpad{Name: "test", Event: "Bought(address,uint256,uint256)", Buy: true, TokenWord: 0, TokenAmountWord: 1, QuoteAmountWord: 2}
+	registry, err := NewRegistry(append(DefaultLaunchpads, launchpad)...)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	if got, ok := registry.Lookup(launchpad.Topic()); !ok || got != launchpad {
+		t.Errorf("Lookup() = %+v, %v, want %+v", got, ok, launchpad)
+	}
+	if _, ok := registry.Lookup(FlapLaunchpads[0].Topic()); !ok {
+		t.Error("Lookup() default launchpad not found")
+	}
+	// Other registries and the package-level functions are not affected.
+	if _, ok := Lookup(launchpad.Topic()); ok {
+		t.Error("Lookup() found a launchpad of another registry")
+	}
+	var defaults *Registry
+	if _, ok := defaults.Lookup(FourMemeLaunchpads[0].Topic()); !ok {
+		t.Error("nil Registry.Lookup() default launchpad not found")
+	}
+
+	if _, err := NewRegistry(Launchpad{Name: "broken"}); err == nil {
+		t.Error("NewRegistry() without event error = nil")
+	}
+	if _, err := NewRegistry(Launchpad{Name: "broken", Event: "Bought(address,uint256,uint256)", TokenWord: -1}); err == nil {
+		t.Error("NewRegistry() negative word error = nil")
+	}
+}
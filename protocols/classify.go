@@ -0,0 +1,167 @@
+package protocols
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/bondingcurve"
+	"github.com/48Club/bscexorcist/protocols/pancakeinfinity"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/48Club/bscexorcist/protocols/transferflow"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxClass classifies a transaction by the shape of its swaps.
+type TxClass int
+
+const (
+	// TxNoSwap is a transaction without swaps.
+	TxNoSwap TxClass = iota
+	// TxSingleSwap is a transaction with exactly one swap.
+	TxSingleSwap
+	// TxMultiHop is a transaction with several swaps that do not return to the start token,
+	// or whose tokens could not be resolved.
+	TxMultiHop
+	// TxCyclicArbitrage is a transaction whose swap path returns to the token it started from,
+	// each hop trading the output of the previous one, and that ends with more of the start
+	// token and none of the other tokens.
+	TxCyclicArbitrage
+)
+
+// String returns the class name.
+func (c TxClass) String() string {
+	switch c {
+	case TxNoSwap:
+		return "no-swap"
+	case TxSingleSwap:
+		return "single-swap"
+	case TxMultiHop:
+		return "multi-hop"
+	case TxCyclicArbitrage:
+		return "cyclic-arbitrage"
+	}
+	return "unknown"
+}
+
+// fromToTokenSwap is implemented by swaps whose event names the tokens traded.
+type fromToTokenSwap interface {
+	FromToken() common.Address
+	ToToken() common.Address
+}
+
+// ClassifyTransaction classifies a single transaction given its logs and the swaps parsed from them.
+// The tokens of each swap come from the swap event when it names them, from the V4 pool
+// registry pools, or else from the ERC20 transfers into and out of the contract holding
+// the pool's tokens (the V4 PoolManager or Infinity Vault for singleton pools), matched in
+// order. Native BNB counts as WBNB.
+func ClassifyTransaction(logs []*types.Log, swaps []SwapEvent, pools *uniswapv4.PoolRegistry) TxClass {
+	return Config{}.ClassifyTransaction(logs, swaps, pools)
+}
+
+// ClassifyTransaction classifies a transaction like the package-level ClassifyTransaction,
+// counting native BNB as the configured wrapped native token.
+func (c Config) ClassifyTransaction(logs []*types.Log, swaps []SwapEvent, pools *uniswapv4.PoolRegistry) TxClass {
+	switch len(swaps) {
+	case 0:
+		return TxNoSwap
+	case 1:
+		return TxSingleSwap
+	}
+
+	inflows, outflows := tokenFlows(logs)
+	var start, end common.Address
+	net := make(map[common.Address]*big.Int)
+	for i, swap := range swaps {
+		tokenIn, tokenOut, ok := swapTokens(swap, pools, c.wrappedNative(), inflows, outflows)
+		if !ok || (i > 0 && tokenIn != end) {
+			return TxMultiHop
+		}
+		if i == 0 {
+			start = tokenIn
+		}
+		end = tokenOut
+		addNet(net, tokenIn, new(big.Int).Neg(swap.AmountIn()))
+		addNet(net, tokenOut, swap.AmountOut())
+	}
+
+	if start != end || net[start].Sign() <= 0 {
+		return TxMultiHop
+	}
+	for token, amount := range net {
+		if token != start && amount.Sign() != 0 {
+			return TxMultiHop
+		}
+	}
+	return TxCyclicArbitrage
+}
+
+// addNet adds amount to the net balance of token.
+func addNet(net map[common.Address]*big.Int, token common.Address, amount *big.Int) {
+	if net[token] == nil {
+		net[token] = new(big.Int)
+	}
+	net[token].Add(net[token], amount)
+}
+
+// tokenFlows returns the tokens transferred into and out of each account, in log order.
+func tokenFlows(logs []*types.Log) (inflows, outflows map[common.Address][]common.Address) {
+	inflows = make(map[common.Address][]common.Address)
+	outflows = make(map[common.Address][]common.Address)
+	for _, log := range logs {
+		if transfer := transferflow.ParseTransfer(log); transfer != nil {
+			inflows[transfer.To] = append(inflows[transfer.To], transfer.Token)
+			outflows[transfer.From] = append(outflows[transfer.From], transfer.Token)
+		}
+	}
+	return inflows, outflows
+}
+
+// tokenHolder returns the contract whose transfers settle a swap: the pool itself, or the
+// singleton holding the tokens of V4 and Infinity pools.
+func tokenHolder(swap SwapEvent) common.Address {
+	switch s := swap.(type) {
+	case *uniswapv4.V4Swap:
+		return s.PoolManager()
+	case *pancakeinfinity.CLSwap, *pancakeinfinity.BinSwap:
+		return pancakeinfinity.Vault
+	}
+	return swap.PairID()
+}
+
+// swapTokens resolves the input and output tokens of a swap, consuming the next inflow and
+// outflow of its token holder when the swap itself does not name them. Native BNB is
+// reported as wrappedNative.
+func swapTokens(swap SwapEvent, pools *uniswapv4.PoolRegistry, wrappedNative common.Address, inflows, outflows map[common.Address][]common.Address) (tokenIn, tokenOut common.Address, ok bool) {
+	switch s := swap.(type) {
+	case fromToTokenSwap:
+		return tools.NormalizeToken(s.FromToken(), wrappedNative), tools.NormalizeToken(s.ToToken(), wrappedNative), true
+	case *transferflow.InferredSwap:
+		return tools.NormalizeToken(s.TokenIn(), wrappedNative), tools.NormalizeToken(s.TokenOut(), wrappedNative), true
+	case *bondingcurve.CurveSwap:
+		quote := tools.NormalizeToken(s.QuoteToken(), wrappedNative)
+		if s.IsToken0To1() {
+			return quote, s.Token(), true
+		}
+		return s.Token(), quote, true
+	case *uniswapv4.V4Swap:
+		if pools == nil {
+			break
+		}
+		if key, known := pools.Lookup(s.PoolID()); known {
+			token0, token1 := key.Tokens(wrappedNative)
+			if s.IsToken0To1() {
+				return token0, token1, true
+			}
+			return token1, token0, true
+		}
+	}
+
+	holder := tokenHolder(swap)
+	if holder == (common.Address{}) || len(inflows[holder]) == 0 || len(outflows[holder]) == 0 {
+		return common.Address{}, common.Address{}, false
+	}
+	tokenIn, tokenOut = inflows[holder][0], outflows[holder][0]
+	inflows[holder], outflows[holder] = inflows[holder][1:], outflows[holder][1:]
+	return tools.NormalizeToken(tokenIn, wrappedNative), tools.NormalizeToken(tokenOut, wrappedNative), true
+}
@@ -0,0 +1,82 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestClassifyTransaction(t *testing.T) {
+	var (
+		pool1  = common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+		pool2  = common.HexToAddress("0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16")
+		usdt   = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		wbnb   = common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+		cake   = common.HexToAddress("0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82")
+		trader = common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	)
+
+	// hop trades amountIn of tokenIn for amountOut of tokenOut on a V2 pool, token0 in.
+	hop := func(pool, tokenIn, tokenOut common.Address, amountIn, amountOut int64) []*types.Log {
+		return []*types.Log{
+			logtest.Transfer(tokenIn, trader, pool, amountIn),
+			logtest.Transfer(tokenOut, pool, trader, amountOut),
+			logtest.V2Swap(pool, trader, amountIn, 0, 0, amountOut),
+		}
+	}
+
+	tests := []struct {
+		name string
+		hops [][]*types.Log
+		want TxClass
+	}{
+		{"single swap", [][]*types.Log{hop(pool1, usdt, wbnb, 1000, 3)}, TxSingleSwap},
+		{"cycle", [][]*types.Log{hop(pool1, usdt, wbnb, 1000, 3), hop(pool2, wbnb, usdt, 3, 1010)}, TxCyclicArbitrage},
+		{"route", [][]*types.Log{hop(pool1, usdt, wbnb, 1000, 3), hop(pool2, wbnb, cake, 3, 50)}, TxMultiHop},
+		{"broken path", [][]*types.Log{hop(pool1, usdt, wbnb, 1000, 3), hop(pool2, cake, usdt, 50, 1010)}, TxMultiHop},
+		{"intermediate left over", [][]*types.Log{hop(pool1, usdt, wbnb, 1000, 3), hop(pool2, wbnb, usdt, 2, 1010)}, TxMultiHop},
+		{"loss", [][]*types.Log{hop(pool1, usdt, wbnb, 1000, 3), hop(pool2, wbnb, usdt, 3, 990)}, TxMultiHop},
+	}
+	for _, tt := range tests {
+		var logs []*types.Log
+		for _, h := range tt.hops {
+			logs = append(logs, h...)
+		}
+		if got := ClassifyTransaction(logs, ParseSwapEvents(logs), nil); got != tt.want {
+			t.Errorf("ClassifyTransaction() %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyTransactionV4(t *testing.T) {
+	var (
+		manager = common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF")
+		usdt    = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		wbnb    = common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+		trader  = common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	)
+	pools := uniswapv4.NewPoolRegistry(manager)
+	cheap := uniswapv4.PoolKey{Currency0: usdt, Currency1: wbnb, Fee: 500, TickSpacing: 10}
+	dear := uniswapv4.PoolKey{Currency0: usdt, Currency1: wbnb, Fee: 3000, TickSpacing: 60}
+	pools.Register(cheap.ID(), cheap)
+	pools.Register(dear.ID(), dear)
+
+	// swap logs a PoolManager swap with the swapper's balance deltas.
+	swap := func(key uniswapv4.PoolKey, amount0, amount1 int64) *types.Log {
+		return logtest.Log(manager, "Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24)",
+			[]common.Hash{key.ID(), logtest.AddressTopic(trader)},
+			logtest.Word(amount0), logtest.Word(amount1), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(0), logtest.Word(int64(key.Fee)))
+	}
+
+	// usdt -> wbnb on one pool, wbnb -> usdt at a better price on the other.
+	logs := []*types.Log{swap(cheap, -1000, 3), swap(dear, 1010, -3)}
+	if got := ClassifyTransaction(logs, ParseSwapEvents(logs), pools); got != TxCyclicArbitrage {
+		t.Errorf("ClassifyTransaction() V4 arbitrage = %v, want %v", got, TxCyclicArbitrage)
+	}
+	if got := ClassifyTransaction(logs, ParseSwapEvents(logs), nil); got != TxMultiHop {
+		t.Errorf("ClassifyTransaction() V4 arbitrage without registry = %v, want %v", got, TxMultiHop)
+	}
+}
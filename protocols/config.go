@@ -0,0 +1,30 @@
+package protocols
+
+import (
+	"github.com/48Club/bscexorcist/protocols/bondingcurve"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Config holds the chain and deployment specific settings used to decode the logs of a
+// transaction. The zero value decodes BSC logs with the built-in parsers only.
+type Config struct {
+	// HookAdapters decode the events of custom-curve V4 hooks, keyed by hook contract.
+	HookAdapters uniswapv4.HookAdapters
+
+	// Launchpads recognizes the bonding-curve launchpad events; nil uses the defaults.
+	Launchpads *bondingcurve.Registry
+
+	// WrappedNative is the wrapped native token native BNB is counted as; the zero
+	// address uses tools.WBNB.
+	WrappedNative common.Address
+}
+
+// wrappedNative returns the configured wrapped native token.
+func (c Config) wrappedNative() common.Address {
+	if c.WrappedNative == (common.Address{}) {
+		return tools.WBNB
+	}
+	return c.WrappedNative
+}
@@ -0,0 +1,108 @@
+package dodoswap
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/transferflow"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	dodoV1SellBaseTokenSignature = common.HexToHash("0xd8648b6ac54162763c86fd54bf2005af8ecd2f9cb273a5775921fd7f91e17b2d")
+	dodoV1BuyBaseTokenSignature  = common.HexToHash("0xe93ad76094f247c0dafc1c61adc2187de1ac2738f7a3b49cb20b2263420251a3")
+)
+
+// DODOV1Swap implements SwapEvent for DODO V1 classic pools.
+// The base token is treated as token0, so every swap of a pool is oriented the same way
+// whether or not ResolveTokens found its tokens.
+type DODOV1Swap struct {
+	poolID      common.Address
+	sellBase    bool
+	baseAmount  *big.Int
+	quoteAmount *big.Int
+	baseToken   common.Address
+	quoteToken  common.Address
+}
+
+// PairID returns the pool address.
+func (s *DODOV1Swap) PairID() common.Address {
+	return s.poolID
+}
+
+// IsToken0To1 returns true if the swap sells the base token for the quote token.
+func (s *DODOV1Swap) IsToken0To1() bool {
+	return s.sellBase
+}
+
+// Tokens returns the base and quote tokens of the pool, or zero addresses if they were not resolved.
+func (s *DODOV1Swap) Tokens() (base, quote common.Address) {
+	return s.baseToken, s.quoteToken
+}
+
+// ResolveTokens finds the base and quote tokens of the pool among the ERC20 transfers
+// of the swap's transaction: the transfers into and out of the pool of the swapped base
+// and quote amounts. Returns true if both tokens were found.
+func (s *DODOV1Swap) ResolveTokens(logs []*types.Log) bool {
+	for _, log := range logs {
+		transfer := transferflow.ParseTransfer(log)
+		if transfer == nil {
+			continue
+		}
+		// A sale pays base into the pool and receives quote; a purchase the reverse.
+		baseLeg, quoteLeg := transfer.To == s.poolID, transfer.From == s.poolID
+		if !s.sellBase {
+			baseLeg, quoteLeg = quoteLeg, baseLeg
+		}
+		if baseLeg && transfer.Value.Cmp(s.baseAmount) == 0 {
+			s.baseToken = transfer.Token
+		} else if quoteLeg && transfer.Value.Cmp(s.quoteAmount) == 0 {
+			s.quoteToken = transfer.Token
+		}
+	}
+	return s.baseToken != (common.Address{}) && s.quoteToken != (common.Address{})
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *DODOV1Swap) AmountIn() *big.Int {
+	if s.sellBase {
+		return new(big.Int).Set(s.baseAmount)
+	}
+	return new(big.Int).Set(s.quoteAmount)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *DODOV1Swap) AmountOut() *big.Int {
+	if s.sellBase {
+		return new(big.Int).Set(s.quoteAmount)
+	}
+	return new(big.Int).Set(s.baseAmount)
+}
+
+// ParseV1Swap parses a DODO V1 SellBaseToken or BuyBaseToken log into a DODOV1Swap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseV1Swap(log *types.Log) *DODOV1Swap {
+	if len(log.Topics) != 2 || len(log.Data) < 64 {
+		return nil
+	}
+
+	switch log.Topics[0] {
+	case dodoV1SellBaseTokenSignature:
+		// Data layout: payBase, receiveQuote
+		return &DODOV1Swap{
+			poolID:      log.Address,
+			sellBase:    true,
+			baseAmount:  new(big.Int).SetBytes(log.Data[:32]),
+			quoteAmount: new(big.Int).SetBytes(log.Data[32:64]),
+		}
+	case dodoV1BuyBaseTokenSignature:
+		// Data layout: receiveBase, payQuote
+		return &DODOV1Swap{
+			poolID:      log.Address,
+			sellBase:    false,
+			baseAmount:  new(big.Int).SetBytes(log.Data[:32]),
+			quoteAmount: new(big.Int).SetBytes(log.Data[32:64]),
+		}
+	}
+	return nil
+}
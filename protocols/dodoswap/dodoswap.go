@@ -4,11 +4,12 @@ package dodoswap
 import (
 	"math/big"
 
+	"github.com/48Club/bscexorcist/protocols/tools"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// DODOSwap implements SwapEvent for DODOSwap protocol.
+// DODOSwap implements SwapEvent for DODO V2 pools (DVM, DPP, DSP).
 type DODOSwap struct {
 	poolID     common.Address
 	tokenFrom  common.Address
@@ -17,32 +18,38 @@ type DODOSwap struct {
 	amountTo   *big.Int
 }
 
-// PairID returns a pseudo-address derived from the first 10 bytes of each token in the pair.
+// PairID returns the pool address.
+// Each DVM/DPP/DSP pool holds its own liquidity, so pools on the same token pair are kept apart.
 func (s *DODOSwap) PairID() common.Address {
 	return s.poolID
 }
 
-// IsToken0To1 returns true if the swap direction is token0 -> token1.
+// FromToken returns the token sold into the pool.
+func (s *DODOSwap) FromToken() common.Address {
+	return s.tokenFrom
+}
+
+// ToToken returns the token bought from the pool.
+func (s *DODOSwap) ToToken() common.Address {
+	return s.tokenTo
+}
+
+// IsToken0To1 returns true if the swap direction is token0 -> token1, where token0 is
+// the lower token address. DODOSwap names the tokens traded but not which one is the
+// pool's base token, so the pair is oriented by address, keeping the orientation stable
+// for every swap of a pool.
 func (s *DODOSwap) IsToken0To1() bool {
-	return isTokenAFirst(s.tokenFrom, s.tokenTo)
+	return tools.IsSortedPair(s.tokenFrom, s.tokenTo)
 }
 
-// AmountIn returns the input amount for the swap.
+// AmountIn returns the amount of FromToken sold into the pool.
 func (s *DODOSwap) AmountIn() *big.Int {
-	if isTokenAFirst(s.tokenFrom, s.tokenTo) {
-		// tokenFrom is token0, so amountFrom is the input amount
-		return new(big.Int).Set(s.amountFrom)
-	}
-	return new(big.Int).Set(s.amountTo)
+	return new(big.Int).Set(s.amountFrom)
 }
 
-// AmountOut returns the output amount for the swap.
+// AmountOut returns the amount of ToToken bought from the pool.
 func (s *DODOSwap) AmountOut() *big.Int {
-	if isTokenAFirst(s.tokenFrom, s.tokenTo) {
-		// tokenFrom is token0, so amountFrom is the input amount
-		return new(big.Int).Set(s.amountTo)
-	}
-	return new(big.Int).Set(s.amountFrom)
+	return new(big.Int).Set(s.amountTo)
 }
 
 // ParseSwap parses a DODOSwap log into a DODOSwap struct.
@@ -56,32 +63,10 @@ func ParseSwap(log *types.Log) *DODOSwap {
 	toToken := common.BytesToAddress(log.Data[32:64])
 
 	return &DODOSwap{
-		poolID:     calcPoolID(fromToken, toToken),
+		poolID:     log.Address,
 		tokenFrom:  fromToken,
 		tokenTo:    toToken,
 		amountFrom: new(big.Int).SetBytes(log.Data[64:96]),
 		amountTo:   new(big.Int).SetBytes(log.Data[96:128]),
 	}
 }
-
-func isTokenAFirst(tkA, tkB common.Address) bool {
-	tokenARep := new(big.Int).SetBytes(tkA.Bytes())
-	tokenBRep := new(big.Int).SetBytes(tkB.Bytes())
-
-	return tokenARep.Cmp(tokenBRep) < 0
-}
-
-// calcPoolID returns a pseudo-address derived from the first 10 bytes of each sorted tokenFrom/tokenTo.
-func calcPoolID(tkA, tkB common.Address) (pool common.Address) {
-	tokenARep := new(big.Int).SetBytes(tkA.Bytes())
-	tokenBRep := new(big.Int).SetBytes(tkB.Bytes())
-	if tokenARep.Cmp(tokenBRep) < 0 {
-		copy(pool[:], tkA.Bytes()[:10])
-		copy(pool[10:], tkB.Bytes()[:10])
-		return pool
-	} else {
-		copy(pool[:], tkB.Bytes()[:10])
-		copy(pool[10:], tkA.Bytes()[:10])
-	}
-	return pool
-}
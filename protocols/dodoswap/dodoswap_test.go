@@ -0,0 +1,113 @@
+package dodoswap
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	usdt   = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	usdc   = common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d")
+	trader = common.HexToAddress("0x946d7590DA72429897A765972Ad7922e7040483d")
+)
+
+func TestParseSwap(t *testing.T) {
+	pool := common.HexToAddress("0x6098A5638d8D7e9Ed2f952d35B2b67c34EC6B476")
+	log := logtest.Log(pool, "DODOSwap(address,address,uint256,uint256,address,address)", nil,
+		logtest.AddressWord(usdt), logtest.AddressWord(usdc), logtest.Word(1000), logtest.Word(998),
+		logtest.AddressWord(trader), logtest.AddressWord(trader))
+
+	swap := ParseSwap(log)
+	if swap == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+	if swap.PairID() != pool || swap.FromToken() != usdt || swap.ToToken() != usdc {
+		t.Errorf("ParseSwap() = pool %s, %s -> %s", swap.PairID().Hex(), swap.FromToken().Hex(), swap.ToToken().Hex())
+	}
+	if !swap.IsToken0To1() || swap.AmountIn().Int64() != 1000 || swap.AmountOut().Int64() != 998 {
+		t.Errorf("ParseSwap() = 0 to 1 %v, %v -> %v, want token0 -> token1, 1000 -> 998", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+
+	// DODOSwap does not tell the base token, so the reverse trade is oriented by address too.
+	reverse := ParseSwap(logtest.Log(pool, "DODOSwap(address,address,uint256,uint256,address,address)", nil,
+		logtest.AddressWord(usdc), logtest.AddressWord(usdt), logtest.Word(998), logtest.Word(995),
+		logtest.AddressWord(trader), logtest.AddressWord(trader)))
+	if reverse.IsToken0To1() || reverse.AmountIn().Int64() != 998 || reverse.AmountOut().Int64() != 995 {
+		t.Errorf("ParseSwap() reverse = 0 to 1 %v, %v -> %v, want token1 -> token0, 998 -> 995", reverse.IsToken0To1(), reverse.AmountIn(), reverse.AmountOut())
+	}
+
+	// Another pool of the same pair is a separate market.
+	other := *log
+	other.Address = common.HexToAddress("0xBe60d4c4250438344bEC816Ec2deC99925dEb4c7")
+	if ParseSwap(&other).PairID() == swap.PairID() {
+		t.Error("ParseSwap() pools of the same pair share a PairID")
+	}
+}
+
+func TestParseV1Swap(t *testing.T) {
+	pool := common.HexToAddress("0x327134dE48fcDD75320f4c32498D1980470249ae")
+	tests := []struct {
+		name            string
+		event           string
+		wantSellBase    bool
+		wantIn, wantOut int64
+	}{
+		{name: "sell base", event: "SellBaseToken(address,uint256,uint256)", wantSellBase: true, wantIn: 10, wantOut: 3000},
+		{name: "buy base", event: "BuyBaseToken(address,uint256,uint256)", wantSellBase: false, wantIn: 3000, wantOut: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logtest.Log(pool, tt.event, []common.Hash{logtest.AddressTopic(trader)}, logtest.Word(10), logtest.Word(3000))
+
+			swap := ParseV1Swap(log)
+			if swap == nil {
+				t.Fatal("ParseV1Swap() = nil")
+			}
+			if swap.PairID() != pool || swap.IsToken0To1() != tt.wantSellBase {
+				t.Errorf("ParseV1Swap() = pool %s, sell base %v", swap.PairID().Hex(), swap.IsToken0To1())
+			}
+			if swap.AmountIn().Int64() != tt.wantIn || swap.AmountOut().Int64() != tt.wantOut {
+				t.Errorf("ParseV1Swap() amounts = %v -> %v, want %d -> %d", swap.AmountIn(), swap.AmountOut(), tt.wantIn, tt.wantOut)
+			}
+		})
+	}
+
+	unknown := logtest.Log(pool, "ChargeMaintainerFee(address,bool,uint256)", []common.Hash{logtest.AddressTopic(trader)}, logtest.Word(1), logtest.Word(2))
+	if swap := ParseV1Swap(unknown); swap != nil {
+		t.Errorf("ParseV1Swap() other event = %+v, want nil", swap)
+	}
+}
+
+func TestResolveTokens(t *testing.T) {
+	// Base USDC sorts above quote USDT; the pool is still oriented base first once resolved.
+	pool := common.HexToAddress("0x327134dE48fcDD75320f4c32498D1980470249ae")
+	sell := ParseV1Swap(logtest.Log(pool, "SellBaseToken(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(trader)}, logtest.Word(10), logtest.Word(9)))
+	buy := ParseV1Swap(logtest.Log(pool, "BuyBaseToken(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(trader)}, logtest.Word(10), logtest.Word(11)))
+	if !sell.IsToken0To1() || buy.IsToken0To1() {
+		t.Fatalf("IsToken0To1() unresolved = %v, %v, want base as token0", sell.IsToken0To1(), buy.IsToken0To1())
+	}
+
+	maintainer := common.HexToAddress("0x95C4F5b83aA70810D4f142d58e5F7242Bd891CB0")
+	if !sell.ResolveTokens([]*types.Log{
+		logtest.Transfer(usdt, pool, trader, 9),
+		logtest.Transfer(usdt, pool, maintainer, 1),
+		logtest.Transfer(usdc, trader, pool, 10),
+	}) {
+		t.Fatal("ResolveTokens() sale = false")
+	}
+	if base, quote := sell.Tokens(); base != usdc || quote != usdt {
+		t.Errorf("Tokens() = %s, %s, want USDC, USDT", base.Hex(), quote.Hex())
+	}
+	if !buy.ResolveTokens([]*types.Log{
+		logtest.Transfer(usdc, pool, trader, 10),
+		logtest.Transfer(usdt, trader, pool, 11),
+	}) {
+		t.Fatal("ResolveTokens() purchase = false")
+	}
+	if !sell.IsToken0To1() || buy.IsToken0To1() {
+		t.Errorf("IsToken0To1() resolved = %v, %v, want base as token0", sell.IsToken0To1(), buy.IsToken0To1())
+	}
+}
@@ -0,0 +1,41 @@
+package protocols
+
+import (
+	"github.com/48Club/bscexorcist/protocols/transferflow"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HeuristicSwap is implemented by swaps that were inferred from indirect evidence
+// rather than decoded from a swap event.
+type HeuristicSwap interface {
+	Heuristic() bool
+}
+
+// IsHeuristic reports whether a swap was inferred rather than decoded from a swap event.
+func IsHeuristic(swap SwapEvent) bool {
+	heuristic, ok := swap.(HeuristicSwap)
+	return ok && heuristic.Heuristic()
+}
+
+// ParseSwapEventsWithFallback extracts swap events like ParseSwapEvents and additionally
+// infers swaps from ERC20 Transfer events for contracts whose swap events are not recognized.
+// Inferred swaps are *transferflow.InferredSwap values and are reported by IsHeuristic.
+func ParseSwapEventsWithFallback(logs []*types.Log) []SwapEvent {
+	return Config{}.ParseSwapEventsWithFallback(logs)
+}
+
+// ParseSwapEventsWithFallback extracts swap events like the package-level
+// ParseSwapEventsWithFallback, also decoding the events of the configured hooks and launchpads.
+func (c Config) ParseSwapEventsWithFallback(logs []*types.Log) []SwapEvent {
+	swaps, decoded := c.parseSwapEvents(logs)
+
+	skip := func(account common.Address) bool {
+		return decoded[account]
+	}
+	for _, swap := range transferflow.InferSwaps(logs, c.wrappedNative(), skip) {
+		swaps = append(swaps, swap)
+	}
+
+	return swaps
+}
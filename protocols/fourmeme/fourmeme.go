@@ -1,52 +1,26 @@
 // Package fourmeme provides swap event parsing for fourmeme protocols.
+//
+// Deprecated: FourMeme events are parsed by the bondingcurve package together with
+// other launchpads. This package forwards to it.
 package fourmeme
 
 import (
-	"math/big"
-
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/48Club/bscexorcist/protocols/bondingcurve"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // FourMemeSwap implements SwapEvent for FourMemeSwap protocol.
-type FourMemeSwap struct {
-	tokenID common.Address
-	buySide bool
-}
-
-var (
-	fourMemeSwapBuySignature  = common.HexToHash("0x7db52723a3b2cdd6164364b3b766e65e540d7be48ffa89582956d8eaebe62942")
-	fourMemeSwapSellSignature = common.HexToHash("0x0a5575b3648bae2210cee56bf33254cc1ddfbc7bf637c0af2ac18b14fb1bae19")
-)
-
-// PairID returns a pseudo-address derived from the first 10 bytes of each token in the pair.
-func (s *FourMemeSwap) PairID() common.Address {
-	return s.tokenID
-}
+//
+// Deprecated: use bondingcurve.CurveSwap.
+type FourMemeSwap = bondingcurve.CurveSwap
 
-// IsToken0To1 returns true if the swap direction is token0 -> token1.
-func (s *FourMemeSwap) IsToken0To1() bool {
-	return s.buySide
-}
-
-// AmountIn returns the input amount for the swap.
-func (s *FourMemeSwap) AmountIn() *big.Int {
-	return big.NewInt(0)
-}
-
-// AmountOut returns the output amount for the swap.
-func (s *FourMemeSwap) AmountOut() *big.Int {
-	return big.NewInt(0)
-}
+// launchpads recognizes the FourMeme events only.
+var launchpads = bondingcurve.MustNewRegistry(bondingcurve.FourMemeLaunchpads...)
 
-// ParseSwap parses a FourmemeSwap log into a FourmemeSwap struct.
-// Returns nil if the log is not a valid swap event.
+// ParseSwap parses a FourMeme TokenPurchase or TokenSale log into a FourMemeSwap struct.
+// Returns nil if the log is not a valid FourMeme swap event.
+//
+// Deprecated: use bondingcurve.ParseSwap, which recognizes every default launchpad.
 func ParseSwap(log *types.Log) *FourMemeSwap {
-	if len(log.Topics) != 1 || len(log.Data) < 32 {
-		return nil
-	}
-	return &FourMemeSwap{
-		tokenID: common.BytesToAddress(log.Data[:32]),
-		buySide: log.Topics[0] == fourMemeSwapBuySignature,
-	}
+	return launchpads.ParseSwap(log)
 }
@@ -0,0 +1,27 @@
+package fourmeme
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	manager := common.HexToAddress("0x5c952063c7fc8610FFDB798152D69F0B9550762b")
+	token := common.HexToAddress("0x4B0F1812e5Df2A09796481Ff14017e6005508003")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+
+	purchase := logtest.Log(manager, "TokenPurchase(address,address,uint256,uint256)", nil,
+		logtest.AddressWord(token), logtest.AddressWord(trader), logtest.Word(5000), logtest.Word(10))
+	if swap := ParseSwap(purchase); swap == nil || swap.PairID() != token || !swap.IsToken0To1() {
+		t.Errorf("ParseSwap() = %+v, want a purchase of the token", swap)
+	}
+
+	// Other launchpads are left to the bondingcurve package.
+	flap := logtest.Log(manager, "TokenBought(uint256,address,address,uint256,uint256,uint256,uint256)", nil,
+		logtest.Word(1), logtest.AddressWord(token), logtest.AddressWord(trader), logtest.Word(5000), logtest.Word(10), logtest.Word(0), logtest.Word(0))
+	if swap := ParseSwap(flap); swap != nil {
+		t.Errorf("ParseSwap() flap event = %+v, want nil", swap)
+	}
+}
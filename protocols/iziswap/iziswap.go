@@ -0,0 +1,90 @@
+// Package iziswap provides swap event parsing for iZiSwap pools.
+package iziswap
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// IZiSwap implements SwapEvent for iZiSwap pools.
+// iZiSwap always orders tokenX below tokenY, so tokenX is token0.
+type IZiSwap struct {
+	pool         common.Address
+	tokenX       common.Address
+	tokenY       common.Address
+	fee          uint32
+	sellXEarnY   bool
+	amountX      *big.Int
+	amountY      *big.Int
+	currentPoint int32
+}
+
+// PairID returns the pool address.
+func (s *IZiSwap) PairID() common.Address {
+	return s.pool
+}
+
+// IsToken0To1 returns true if the swap sells tokenX for tokenY.
+func (s *IZiSwap) IsToken0To1() bool {
+	return s.sellXEarnY
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *IZiSwap) AmountIn() *big.Int {
+	if s.sellXEarnY {
+		return new(big.Int).Set(s.amountX)
+	}
+	return new(big.Int).Set(s.amountY)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *IZiSwap) AmountOut() *big.Int {
+	if s.sellXEarnY {
+		return new(big.Int).Set(s.amountY)
+	}
+	return new(big.Int).Set(s.amountX)
+}
+
+// TokenX returns the pool's tokenX.
+func (s *IZiSwap) TokenX() common.Address {
+	return s.tokenX
+}
+
+// TokenY returns the pool's tokenY.
+func (s *IZiSwap) TokenY() common.Address {
+	return s.tokenY
+}
+
+// Fee returns the pool fee tier.
+func (s *IZiSwap) Fee() uint32 {
+	return s.fee
+}
+
+// CurrentPoint returns the pool's point (tick) after the swap.
+func (s *IZiSwap) CurrentPoint() int32 {
+	return s.currentPoint
+}
+
+// ParseSwap parses an iZiSwap swap log into an IZiSwap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseSwap(log *types.Log) *IZiSwap {
+	// Topics: signature, tokenX, tokenY, fee
+	// Data layout: sellXEarnY, amountX, amountY, currentPoint
+	if len(log.Topics) != 4 || len(log.Data) < 128 {
+		return nil
+	}
+
+	return &IZiSwap{
+		pool:         log.Address,
+		tokenX:       common.BytesToAddress(log.Topics[1].Bytes()),
+		tokenY:       common.BytesToAddress(log.Topics[2].Bytes()),
+		fee:          uint32(log.Topics[3].Big().Uint64()),
+		sellXEarnY:   new(big.Int).SetBytes(log.Data[:32]).Sign() != 0,
+		amountX:      new(big.Int).SetBytes(log.Data[32:64]),
+		amountY:      new(big.Int).SetBytes(log.Data[64:96]),
+		currentPoint: int32(tools.DecodeSignedInt256(log.Data[96:128]).Int64()),
+	}
+}
@@ -0,0 +1,44 @@
+package iziswap
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	pool := common.HexToAddress("0x1CE3082de766ebFe1b4dB39f616426631BbB29aC")
+	tokenX := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	tokenY := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	indexed := []common.Hash{logtest.AddressTopic(tokenX), logtest.AddressTopic(tokenY), logtest.IntTopic(2000)}
+
+	tests := []struct {
+		name            string
+		sellXEarnY      bool
+		wantIn, wantOut int64
+	}{
+		{name: "sell x", sellXEarnY: true, wantIn: 3000, wantOut: 10},
+		{name: "sell y", sellXEarnY: false, wantIn: 10, wantOut: 3000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logtest.Log(pool, "Swap(address,address,uint24,bool,uint256,uint256,int24)", indexed,
+				logtest.Bool(tt.sellXEarnY), logtest.Word(3000), logtest.Word(10), logtest.Word(-46000))
+
+			swap := ParseSwap(log)
+			if swap == nil {
+				t.Fatal("ParseSwap() = nil")
+			}
+			if swap.PairID() != pool || swap.TokenX() != tokenX || swap.TokenY() != tokenY || swap.Fee() != 2000 {
+				t.Errorf("ParseSwap() = pool %s, tokens %s/%s, fee %d", swap.PairID().Hex(), swap.TokenX().Hex(), swap.TokenY().Hex(), swap.Fee())
+			}
+			if swap.IsToken0To1() != tt.sellXEarnY || swap.AmountIn().Int64() != tt.wantIn || swap.AmountOut().Int64() != tt.wantOut {
+				t.Errorf("ParseSwap() = 0 to 1 %v, %v -> %v, want %v, %d -> %d", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut(), tt.sellXEarnY, tt.wantIn, tt.wantOut)
+			}
+			if swap.CurrentPoint() != -46000 {
+				t.Errorf("ParseSwap() current point = %d, want -46000", swap.CurrentPoint())
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package protocols
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/transferflow"
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/48Club/bscexorcist/protocols/uniswapv3"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LiquidityEvent represents a liquidity deposit or withdrawal with a unified interface for all supported protocols.
+type LiquidityEvent interface {
+	PairID() common.Address
+	// Provider is the owner of the liquidity.
+	Provider() common.Address
+	// PositionID identifies the position within the pool.
+	PositionID() common.Hash
+	IsAdd() bool
+	// Amount0 and Amount1 are the token amounts deposited or withdrawn, nil if the event does not report them.
+	Amount0() *big.Int
+	Amount1() *big.Int
+}
+
+// FeeCollector is implemented by liquidity withdrawals that know the fees collected with them.
+type FeeCollector interface {
+	Fees() (token0, token1 *big.Int)
+}
+
+// PositionHolder is implemented by liquidity events of positions that may be held through a position manager NFT.
+type PositionHolder interface {
+	Holder() common.Address
+}
+
+// LiquidityOwner returns the owner of the liquidity: the holder of the position manager NFT
+// when it is known, else the Provider.
+func LiquidityOwner(event LiquidityEvent) common.Address {
+	if holder, ok := event.(PositionHolder); ok && holder.Holder() != (common.Address{}) {
+		return holder.Holder()
+	}
+	return event.Provider()
+}
+
+// CollectedFees returns the fees collected with a liquidity withdrawal, nil if unknown.
+func CollectedFees(event LiquidityEvent) (token0, token1 *big.Int) {
+	if collector, ok := event.(FeeCollector); ok {
+		return collector.Fees()
+	}
+	return nil, nil
+}
+
+var (
+	// Uniswap V2 and compatible Mint and Burn event signatures
+	uniswapV2MintSignature = common.HexToHash("0x4c209b5fc8ad50758f13e2e1088ba56a560dff690a1c6fef26394f4c03821c4f")
+	uniswapV2BurnSignature = common.HexToHash("0xdccd412f0b1252819cb1fd330b93224ca42612892bb3f4f789976e6d81936496")
+
+	// Uniswap V3 and compatible position event signatures
+	uniswapV3MintSignature    = common.HexToHash("0x7a53080ba414158be7ec69b987b5fb7d07dee101fe85488f0853ae16239d0bde")
+	uniswapV3BurnSignature    = common.HexToHash("0x0c396cd989a39f4459b5fa1aed6a9a8dcdbc45908acfd67e028cd568da98982c")
+	uniswapV3CollectSignature = common.HexToHash("0x70935338e69775456a85ddef226c395fb668b63fa0115f5f20610b388e6ca9c0")
+
+	// Uniswap V4 PoolManager ModifyLiquidity event signature
+	uniswapV4ModifyLiquiditySignature = common.HexToHash("0xf208f4912782fd25c7f114ca3723a2d5dd6f3bcc3ac8db5af63baa85f711d5ec")
+)
+
+// ParseLiquidityEvents extracts liquidity deposits and withdrawals from a slice of logs for a single transaction.
+// V3 Collect events are attached to the preceding Burn of the same position, see CollectedFees.
+// The provider of a V2 Mint is the recipient of the LP tokens the pair minted before it.
+// V3 and V4 positions held through a position manager NFT are told apart by token id, see
+// resolvePositionNFTs.
+func ParseLiquidityEvents(logs []*types.Log) []LiquidityEvent {
+	var (
+		events      []LiquidityEvent
+		burns       []*uniswapv3.PositionChange
+		lpRecipient = make(map[common.Address]common.Address)
+	)
+
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+
+		var event LiquidityEvent
+		switch log.Topics[0] {
+		case transferflow.TransferSignature:
+			if transfer := transferflow.ParseTransfer(log); transfer != nil && transfer.From == (common.Address{}) && transfer.To != (common.Address{}) {
+				lpRecipient[log.Address] = transfer.To
+			}
+		case uniswapV2MintSignature:
+			if mint := uniswapv2.ParseMint(log); mint != nil {
+				if provider, ok := lpRecipient[log.Address]; ok {
+					mint.SetProvider(provider)
+				}
+				event = mint
+			}
+		case uniswapV2BurnSignature:
+			event = asLiquidityEvent(uniswapv2.ParseBurn(log))
+		case uniswapV3MintSignature:
+			event = asLiquidityEvent(uniswapv3.ParseMint(log))
+		case uniswapV3BurnSignature:
+			if burn := uniswapv3.ParseBurn(log); burn != nil {
+				burns = append(burns, burn)
+				event = burn
+			}
+		case uniswapV3CollectSignature:
+			if collect := uniswapv3.ParseCollect(log); collect != nil {
+				for i := len(burns) - 1; i >= 0; i-- {
+					if burns[i].AddCollect(collect) {
+						break
+					}
+				}
+			}
+		case uniswapV4ModifyLiquiditySignature:
+			event = asLiquidityEvent(uniswapv4.ParseModifyLiquidity(log))
+		}
+
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+
+	resolvePositionNFTs(logs, events)
+	return events
+}
+
+// nftKey identifies a position manager NFT.
+type nftKey struct {
+	manager common.Address
+	tokenID common.Hash
+}
+
+// resolvePositionNFTs links the V3 position changes of a transaction to the position manager
+// NFTs they were made for, from the manager's IncreaseLiquidity and DecreaseLiquidity events
+// of the same liquidity. The holder of an NFT minted in the transaction is recorded on its
+// V3 and V4 position changes.
+func resolvePositionNFTs(logs []*types.Log, events []LiquidityEvent) {
+	var changes []*uniswapv3.ManagerChange
+	minted := make(map[nftKey]common.Address)
+	for _, log := range logs {
+		if change := uniswapv3.ParseManagerChange(log); change != nil {
+			changes = append(changes, change)
+		} else if transfer := transferflow.ParseNFTTransfer(log); transfer != nil && transfer.From == (common.Address{}) {
+			minted[nftKey{transfer.Token, common.BigToHash(transfer.Value)}] = transfer.To
+		}
+	}
+
+	for _, event := range events {
+		switch e := event.(type) {
+		case *uniswapv3.PositionChange:
+			for i, change := range changes {
+				if change != nil && change.Manager == e.Provider() && change.Increase == e.IsAdd() && change.Liquidity.Cmp(e.Liquidity()) == 0 {
+					e.SetTokenID(change.TokenID)
+					changes[i] = nil
+					break
+				}
+			}
+			if e.TokenID() != nil {
+				if holder, ok := minted[nftKey{e.Provider(), common.BigToHash(e.TokenID())}]; ok {
+					e.SetHolder(holder)
+				}
+			}
+		case *uniswapv4.ModifyLiquidity:
+			if holder, ok := minted[nftKey{e.Provider(), common.BigToHash(e.TokenID())}]; ok {
+				e.SetHolder(holder)
+			}
+		}
+	}
+}
+
+// asLiquidityEvent converts a parser result to a LiquidityEvent, keeping a nil result a nil interface.
+func asLiquidityEvent[T any, P interface {
+	*T
+	LiquidityEvent
+}](event P) LiquidityEvent {
+	if event == nil {
+		return nil
+	}
+	return event
+}
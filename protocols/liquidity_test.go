@@ -0,0 +1,36 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/uniswapv3"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseLiquidityEventsPositionNFT(t *testing.T) {
+	var (
+		manager = common.HexToAddress("0x46A15B0b27311cedF172AB29E4f4766fbE7F4364")
+		pool    = common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+		holder  = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+	position := []common.Hash{logtest.AddressTopic(manager), logtest.IntTopic(-10), logtest.IntTopic(10)}
+
+	// NonfungiblePositionManager.mint: pool Mint, NFT mint, IncreaseLiquidity.
+	events := ParseLiquidityEvents([]*types.Log{
+		logtest.Log(pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", position,
+			logtest.AddressWord(manager), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+		logtest.Log(manager, "Transfer(address,address,uint256)",
+			[]common.Hash{logtest.AddressTopic(common.Address{}), logtest.AddressTopic(holder), logtest.IntTopic(42)}),
+		logtest.Log(manager, "IncreaseLiquidity(uint256,uint128,uint256,uint256)",
+			[]common.Hash{logtest.IntTopic(42)}, logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)),
+	})
+	if len(events) != 1 {
+		t.Fatalf("ParseLiquidityEvents() = %d events, want 1", len(events))
+	}
+	mint := events[0].(*uniswapv3.PositionChange)
+	if mint.TokenID() == nil || mint.TokenID().Int64() != 42 || LiquidityOwner(mint) != holder || mint.Provider() != manager {
+		t.Errorf("ParseLiquidityEvents() = token %v, owner %s, want NFT 42 of %s", mint.TokenID(), LiquidityOwner(mint).Hex(), holder.Hex())
+	}
+}
@@ -0,0 +1,74 @@
+// Package liquiditybook provides swap event parsing for Liquidity Book (bin-based AMM) pairs.
+package liquiditybook
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LBSwap implements SwapEvent for Liquidity Book pairs.
+type LBSwap struct {
+	pool       common.Address
+	binID      uint32
+	amountXIn  *big.Int
+	amountYIn  *big.Int
+	amountXOut *big.Int
+	amountYOut *big.Int
+}
+
+// PairID returns the pair address.
+func (s *LBSwap) PairID() common.Address {
+	return s.pool
+}
+
+// IsToken0To1 returns true if the swap direction is tokenX -> tokenY.
+func (s *LBSwap) IsToken0To1() bool {
+	return s.amountXIn.Sign() > 0
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *LBSwap) AmountIn() *big.Int {
+	if s.amountXIn.Sign() > 0 {
+		return new(big.Int).Set(s.amountXIn)
+	}
+	return new(big.Int).Set(s.amountYIn)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *LBSwap) AmountOut() *big.Int {
+	if s.amountXIn.Sign() > 0 {
+		return new(big.Int).Set(s.amountYOut)
+	}
+	return new(big.Int).Set(s.amountXOut)
+}
+
+// BinID returns the id of the bin the swap was executed in.
+// A sandwich front-run shows up as a move of the active bin before the victim swap.
+func (s *LBSwap) BinID() uint32 {
+	return s.binID
+}
+
+// ParseSwap parses a Liquidity Book swap log into a LBSwap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseSwap(log *types.Log) *LBSwap {
+	// Data layout: id, amountsIn, amountsOut, volatilityAccumulator, totalFees, protocolFees
+	if len(log.Topics) != 3 || len(log.Data) < 192 {
+		return nil
+	}
+
+	binID := new(big.Int).SetBytes(log.Data[:32])
+	amountXIn, amountYIn := tools.DecodePackedUint128(log.Data[32:64])
+	amountXOut, amountYOut := tools.DecodePackedUint128(log.Data[64:96])
+
+	return &LBSwap{
+		pool:       log.Address,
+		binID:      uint32(binID.Uint64()),
+		amountXIn:  amountXIn,
+		amountYIn:  amountYIn,
+		amountXOut: amountXOut,
+		amountYOut: amountYOut,
+	}
+}
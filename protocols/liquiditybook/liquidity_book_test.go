@@ -0,0 +1,53 @@
+package liquiditybook
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	pair := common.HexToAddress("0x4e7bd0A1fC0C4B2bF5C4b3c52E7E6E1cA3f1D9b2")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	// packed returns amounts packed as bytes32: tokenX in the lower 128 bits, tokenY in the upper.
+	packed := func(x, y int64) []byte {
+		return logtest.BigWord(new(big.Int).Add(new(big.Int).Lsh(big.NewInt(y), 128), big.NewInt(x)))
+	}
+
+	tests := []struct {
+		name            string
+		amountsIn       []byte
+		amountsOut      []byte
+		wantXToY        bool
+		wantIn, wantOut int64
+	}{
+		{name: "x to y", amountsIn: packed(1000, 0), amountsOut: packed(0, 990), wantXToY: true, wantIn: 1000, wantOut: 990},
+		{name: "y to x", amountsIn: packed(0, 500), amountsOut: packed(490, 0), wantXToY: false, wantIn: 500, wantOut: 490},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logtest.Log(pair, "Swap(address,address,uint24,bytes32,bytes32,uint24,bytes32,bytes32)",
+				[]common.Hash{logtest.AddressTopic(trader), logtest.AddressTopic(trader)},
+				logtest.Word(8388608), tt.amountsIn, tt.amountsOut, logtest.Word(0), packed(3, 0), packed(0, 0))
+
+			swap := ParseSwap(log)
+			if swap == nil {
+				t.Fatal("ParseSwap() = nil")
+			}
+			if swap.PairID() != pair || swap.IsToken0To1() != tt.wantXToY || swap.BinID() != 8388608 {
+				t.Errorf("ParseSwap() = pair %s, x to y %v, bin %d", swap.PairID().Hex(), swap.IsToken0To1(), swap.BinID())
+			}
+			if swap.AmountIn().Int64() != tt.wantIn || swap.AmountOut().Int64() != tt.wantOut {
+				t.Errorf("ParseSwap() amounts = %v -> %v, want %d -> %d", swap.AmountIn(), swap.AmountOut(), tt.wantIn, tt.wantOut)
+			}
+		})
+	}
+
+	short := logtest.Log(pair, "Swap(address,address,uint24,bytes32,bytes32,uint24,bytes32,bytes32)",
+		[]common.Hash{logtest.AddressTopic(trader), logtest.AddressTopic(trader)}, logtest.Word(8388608))
+	if swap := ParseSwap(short); swap != nil {
+		t.Errorf("ParseSwap() truncated log = %+v, want nil", swap)
+	}
+}
@@ -0,0 +1,78 @@
+// Package maverick provides swap event parsing for Maverick V2 pools.
+package maverick
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaverickV2Swap implements SwapEvent for Maverick V2 pools.
+// Maverick V2 orders tokenA below tokenB, so tokenA is token0.
+type MaverickV2Swap struct {
+	pool        common.Address
+	tokenAIn    bool
+	exactOutput bool
+	amount      *big.Int
+	tickLimit   int32
+	amountIn    *big.Int
+	amountOut   *big.Int
+}
+
+// PairID returns the pool address.
+func (s *MaverickV2Swap) PairID() common.Address {
+	return s.pool
+}
+
+// IsToken0To1 returns true if the swap direction is tokenA -> tokenB.
+func (s *MaverickV2Swap) IsToken0To1() bool {
+	return s.tokenAIn
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *MaverickV2Swap) AmountIn() *big.Int {
+	return new(big.Int).Set(s.amountIn)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *MaverickV2Swap) AmountOut() *big.Int {
+	return new(big.Int).Set(s.amountOut)
+}
+
+// ExactOutput returns true if the swap specified its output amount rather than its input.
+func (s *MaverickV2Swap) ExactOutput() bool {
+	return s.exactOutput
+}
+
+// SpecifiedAmount returns the amount requested by the swapper, an output amount for
+// exact-output swaps and an input amount otherwise.
+func (s *MaverickV2Swap) SpecifiedAmount() *big.Int {
+	return new(big.Int).Set(s.amount)
+}
+
+// TickLimit returns the tick the swap was not allowed to move past.
+func (s *MaverickV2Swap) TickLimit() int32 {
+	return s.tickLimit
+}
+
+// ParseSwap parses a Maverick V2 PoolSwap log into a MaverickV2Swap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseSwap(log *types.Log) *MaverickV2Swap {
+	// Data layout: sender, recipient, params.amount, params.tokenAIn, params.exactOutput,
+	// params.tickLimit, amountIn, amountOut
+	if len(log.Topics) != 1 || len(log.Data) < 256 {
+		return nil
+	}
+
+	return &MaverickV2Swap{
+		pool:        log.Address,
+		amount:      new(big.Int).SetBytes(log.Data[64:96]),
+		tokenAIn:    new(big.Int).SetBytes(log.Data[96:128]).Sign() != 0,
+		exactOutput: new(big.Int).SetBytes(log.Data[128:160]).Sign() != 0,
+		tickLimit:   int32(tools.DecodeSignedInt256(log.Data[160:192]).Int64()),
+		amountIn:    new(big.Int).SetBytes(log.Data[192:224]),
+		amountOut:   new(big.Int).SetBytes(log.Data[224:256]),
+	}
+}
@@ -0,0 +1,35 @@
+package maverick
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	pool := common.HexToAddress("0x6dC2FD8B4D3B5A5F0C4b8b2d2c7C9a1E3F4a5B6c")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	// Data layout: sender, recipient, (amount, tokenAIn, exactOutput, tickLimit), amountIn, amountOut
+	log := logtest.Log(pool, "PoolSwap(address,address,(uint256,bool,bool,int32),uint256,uint256)", nil,
+		logtest.AddressWord(trader), logtest.AddressWord(trader),
+		logtest.Word(500), logtest.Bool(false), logtest.Bool(true), logtest.Word(-7),
+		logtest.Word(510), logtest.Word(500))
+
+	swap := ParseSwap(log)
+	if swap == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+	if swap.PairID() != pool || swap.IsToken0To1() || swap.AmountIn().Int64() != 510 || swap.AmountOut().Int64() != 500 {
+		t.Errorf("ParseSwap() = pool %s, 0 to 1 %v, %v -> %v, want tokenB -> tokenA, 510 -> 500",
+			swap.PairID().Hex(), swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+	if !swap.ExactOutput() || swap.SpecifiedAmount().Int64() != 500 || swap.TickLimit() != -7 {
+		t.Errorf("ParseSwap() exact output = %v, specified = %v, tick limit = %d", swap.ExactOutput(), swap.SpecifiedAmount(), swap.TickLimit())
+	}
+
+	log.Data = log.Data[:224]
+	if swap := ParseSwap(log); swap != nil {
+		t.Errorf("ParseSwap() truncated log = %+v, want nil", swap)
+	}
+}
@@ -0,0 +1,97 @@
+package protocols
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NetSwap implements SwapEvent for the net effect of several swaps of one transaction on the same pool.
+type NetSwap struct {
+	pool   common.Address
+	delta0 *big.Int // delta0 > 0 means token0 entered the pool
+	delta1 *big.Int // delta1 > 0 means token1 entered the pool
+	legs   []SwapEvent
+}
+
+// PairID returns the pool shared by the netted swaps.
+func (s *NetSwap) PairID() common.Address {
+	return s.pool
+}
+
+// IsToken0To1 returns true if token0 entered the pool on balance.
+func (s *NetSwap) IsToken0To1() bool {
+	return s.delta0.Sign() > 0
+}
+
+// AmountIn returns the net input amount.
+func (s *NetSwap) AmountIn() *big.Int {
+	if s.IsToken0To1() {
+		return new(big.Int).Set(s.delta0)
+	}
+	return new(big.Int).Set(s.delta1)
+}
+
+// AmountOut returns the net output amount.
+func (s *NetSwap) AmountOut() *big.Int {
+	if s.IsToken0To1() {
+		return new(big.Int).Neg(s.delta1)
+	}
+	return new(big.Int).Neg(s.delta0)
+}
+
+// Neutral returns true if the swaps have no single effective direction: the pool did not
+// gain one token while losing the other, as for a round trip.
+func (s *NetSwap) Neutral() bool {
+	return s.delta0.Sign()*s.delta1.Sign() >= 0
+}
+
+// Legs returns the netted swaps in log order.
+func (s *NetSwap) Legs() []SwapEvent {
+	return s.legs
+}
+
+// Heuristic returns true if any netted swap was inferred rather than decoded, see IsHeuristic.
+func (s *NetSwap) Heuristic() bool {
+	for _, leg := range s.legs {
+		if IsHeuristic(leg) {
+			return true
+		}
+	}
+	return false
+}
+
+// NetSwaps nets the swaps of a single transaction per pool. Pools swapped once keep their
+// swap; pools swapped several times get a *NetSwap in place of their first swap.
+func NetSwaps(swaps []SwapEvent) []SwapEvent {
+	counts := make(map[common.Address]int)
+	for _, swap := range swaps {
+		counts[swap.PairID()]++
+	}
+
+	var netted []SwapEvent
+	nets := make(map[common.Address]*NetSwap)
+	for _, swap := range swaps {
+		pool := swap.PairID()
+		if counts[pool] == 1 {
+			netted = append(netted, swap)
+			continue
+		}
+
+		net, ok := nets[pool]
+		if !ok {
+			net = &NetSwap{pool: pool, delta0: new(big.Int), delta1: new(big.Int)}
+			nets[pool] = net
+			netted = append(netted, net)
+		}
+		in, out := net.delta0, net.delta1
+		if !swap.IsToken0To1() {
+			in, out = out, in
+		}
+		in.Add(in, swap.AmountIn())
+		out.Sub(out, swap.AmountOut())
+		net.legs = append(net.legs, swap)
+	}
+
+	return netted
+}
@@ -0,0 +1,144 @@
+// Package pancakeinfinity provides swap event parsing for PancakeSwap Infinity CL and Bin pool managers.
+package pancakeinfinity
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Vault is the PancakeSwap Infinity Vault on BSC. It holds the currencies of the pools of
+// both the CL and the Bin pool manager and settles their swaps.
+var Vault = common.HexToAddress("0x238a358808379702088667322f80aC48bAd5e6c4")
+
+// swapDelta holds the currency deltas shared by CL and Bin swap events. Like Uniswap V4,
+// the amounts are balance deltas of the swapper: negative for the currency paid into the
+// pool, positive for the currency taken out.
+type swapDelta struct {
+	poolID      [32]byte // poolID is the full 32-byte PoolId, the pool manager is a singleton
+	amount0     *big.Int
+	amount1     *big.Int
+	fee         uint32
+	protocolFee uint16
+}
+
+// PairID returns a pseudo-address derived from the first 20 bytes of poolID.
+func (s *swapDelta) PairID() common.Address {
+	return common.BytesToAddress(s.poolID[:20])
+}
+
+// PoolID returns the full 32-byte PoolId of the pool.
+func (s *swapDelta) PoolID() common.Hash {
+	return s.poolID
+}
+
+// IsToken0To1 returns true if the swap direction is token0 -> token1.
+func (s *swapDelta) IsToken0To1() bool {
+	// The amounts are balance deltas of the swapper: amount0 < 0 means token0 was paid into the pool
+	return s.amount0.Sign() < 0
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *swapDelta) AmountIn() *big.Int {
+	if s.IsToken0To1() {
+		return new(big.Int).Abs(s.amount0)
+	}
+	return new(big.Int).Abs(s.amount1)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *swapDelta) AmountOut() *big.Int {
+	if s.IsToken0To1() {
+		return new(big.Int).Abs(s.amount1)
+	}
+	return new(big.Int).Abs(s.amount0)
+}
+
+// Fee returns the swap fee in hundredths of a bip.
+func (s *swapDelta) Fee() uint32 {
+	return s.fee
+}
+
+// ProtocolFee returns the protocol fee charged on the swap.
+func (s *swapDelta) ProtocolFee() uint16 {
+	return s.protocolFee
+}
+
+// CLSwap implements SwapEvent for PancakeSwap Infinity CLPoolManager pools.
+type CLSwap struct {
+	swapDelta
+	sqrtPriceX96 *big.Int
+	liquidity    *big.Int
+	tick         int32
+}
+
+// SqrtPriceX96 returns the sqrt price of the pool after the swap, as a Q64.96.
+func (s *CLSwap) SqrtPriceX96() *big.Int {
+	return s.sqrtPriceX96
+}
+
+// Liquidity returns the in-range liquidity of the pool after the swap.
+func (s *CLSwap) Liquidity() *big.Int {
+	return s.liquidity
+}
+
+// Tick returns the tick of the pool after the swap.
+func (s *CLSwap) Tick() int32 {
+	return s.tick
+}
+
+// BinSwap implements SwapEvent for PancakeSwap Infinity BinPoolManager pools.
+type BinSwap struct {
+	swapDelta
+	activeID uint32
+}
+
+// ActiveID returns the active bin id of the pool after the swap.
+func (s *BinSwap) ActiveID() uint32 {
+	return s.activeID
+}
+
+// ParseCLSwap parses a CLPoolManager swap log into a CLSwap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseCLSwap(log *types.Log) *CLSwap {
+	// Data layout: amount0, amount1, sqrtPriceX96, liquidity, tick, fee, protocolFee
+	if len(log.Topics) != 3 || len(log.Data) < 224 {
+		return nil
+	}
+
+	return &CLSwap{
+		swapDelta:    parseSwapDelta(log, log.Data[160:192], log.Data[192:224]),
+		sqrtPriceX96: new(big.Int).SetBytes(log.Data[64:96]),
+		liquidity:    new(big.Int).SetBytes(log.Data[96:128]),
+		tick:         int32(tools.DecodeSignedInt256(log.Data[128:160]).Int64()),
+	}
+}
+
+// ParseBinSwap parses a BinPoolManager swap log into a BinSwap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseBinSwap(log *types.Log) *BinSwap {
+	// Data layout: amount0, amount1, activeId, fee, protocolFee
+	if len(log.Topics) != 3 || len(log.Data) < 160 {
+		return nil
+	}
+
+	return &BinSwap{
+		swapDelta: parseSwapDelta(log, log.Data[96:128], log.Data[128:160]),
+		activeID:  uint32(new(big.Int).SetBytes(log.Data[64:96]).Uint64()),
+	}
+}
+
+func parseSwapDelta(log *types.Log, fee, protocolFee []byte) swapDelta {
+	var poolID [32]byte
+	copy(poolID[:], log.Topics[1].Bytes())
+
+	return swapDelta{
+		poolID:      poolID,
+		amount0:     tools.DecodeSignedInt256(log.Data[:32]),
+		amount1:     tools.DecodeSignedInt256(log.Data[32:64]),
+		fee:         uint32(new(big.Int).SetBytes(fee).Uint64()),
+		protocolFee: uint16(new(big.Int).SetBytes(protocolFee).Uint64()),
+	}
+}
@@ -0,0 +1,57 @@
+package pancakeinfinity
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	poolID = common.HexToHash("0x9a7b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9")
+	sender = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+)
+
+func TestParseCLSwap(t *testing.T) {
+	log := logtest.Log(common.HexToAddress("0xa0FfB9c1CE1Fe56963B0321B32E7A0302114058b"),
+		"Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24,uint16)",
+		[]common.Hash{poolID, logtest.AddressTopic(sender)},
+		logtest.Word(-1000), logtest.Word(2000), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(-120), logtest.Word(2500), logtest.Word(32))
+
+	swap := ParseCLSwap(log)
+	if swap == nil {
+		t.Fatal("ParseCLSwap() = nil")
+	}
+	if swap.PoolID() != poolID || swap.PairID() != common.BytesToAddress(poolID[:20]) {
+		t.Errorf("ParseCLSwap() pool = %s, pair = %s", swap.PoolID().Hex(), swap.PairID().Hex())
+	}
+	if !swap.IsToken0To1() || swap.AmountIn().Int64() != 1000 || swap.AmountOut().Int64() != 2000 {
+		t.Errorf("ParseCLSwap() = 0 to 1 %v, %v -> %v, want token0 -> token1, 1000 -> 2000", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+	if swap.Tick() != -120 || swap.Fee() != 2500 || swap.ProtocolFee() != 32 || swap.Liquidity().Int64() != 5000 {
+		t.Errorf("ParseCLSwap() tick = %d, fee = %d, protocol fee = %d, liquidity = %v", swap.Tick(), swap.Fee(), swap.ProtocolFee(), swap.Liquidity())
+	}
+}
+
+func TestParseBinSwap(t *testing.T) {
+	log := logtest.Log(common.HexToAddress("0xC697d2898e0D09264376196696c51D7aBbbAA4a9"),
+		"Swap(bytes32,address,int128,int128,uint24,uint24,uint16)",
+		[]common.Hash{poolID, logtest.AddressTopic(sender)},
+		logtest.Word(1000), logtest.Word(-990), logtest.Word(8388608), logtest.Word(100), logtest.Word(0))
+
+	swap := ParseBinSwap(log)
+	if swap == nil {
+		t.Fatal("ParseBinSwap() = nil")
+	}
+	if swap.IsToken0To1() || swap.AmountIn().Int64() != 990 || swap.AmountOut().Int64() != 1000 {
+		t.Errorf("ParseBinSwap() = 0 to 1 %v, %v -> %v, want token1 -> token0, 990 -> 1000", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+	if swap.ActiveID() != 8388608 || swap.Fee() != 100 {
+		t.Errorf("ParseBinSwap() active id = %d, fee = %d", swap.ActiveID(), swap.Fee())
+	}
+
+	log.Data = log.Data[:128]
+	if swap := ParseBinSwap(log); swap != nil {
+		t.Errorf("ParseBinSwap() truncated log = %+v, want nil", swap)
+	}
+}
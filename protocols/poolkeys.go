@@ -0,0 +1,20 @@
+package protocols
+
+import (
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Uniswap V4 PoolManager initialize event signature, used to learn pool metadata
+var uniswapV4InitializeSignature = common.HexToHash("0xdd466e674ea557f56295e2d0218a125ea4b4f0f6f3307b95f85e6110838d6438")
+
+// ObservePoolKeys feeds the Initialize events of a single transaction into a V4 pool
+// registry. Only events emitted by the registry's PoolManagers are recorded.
+func ObservePoolKeys(registry *uniswapv4.PoolRegistry, logs []*types.Log) {
+	for _, log := range logs {
+		if len(log.Topics) > 0 && log.Topics[0] == uniswapV4InitializeSignature {
+			registry.Observe(log)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package poolverify checks that a swap event emitter is a genuine pool by recomputing
+// its CREATE2 address from known factories and the tokens it traded.
+package poolverify
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Kind distinguishes how a factory derives the CREATE2 salt of its pools.
+type Kind int
+
+const (
+	// KindV2 pools use salt = keccak256(abi.encodePacked(token0, token1)).
+	KindV2 Kind = iota
+	// KindV3 pools use salt = keccak256(abi.encode(token0, token1, fee)).
+	KindV3
+)
+
+// Factory describes a pool deployer and the init code hash of the pools it creates.
+type Factory struct {
+	Name string
+	Kind Kind
+	// Deployer is the CREATE2 sender: the factory, or the separate pool deployer for PancakeSwap V3.
+	Deployer     common.Address
+	InitCodeHash common.Hash
+	// FeeTiers are the fees a V3 factory allows, tried in turn when recomputing addresses.
+	FeeTiers []uint32
+}
+
+// PoolAddress returns the address of the pool for a token pair and, for V3 factories, a fee tier.
+func (f Factory) PoolAddress(token0, token1 common.Address, fee uint32) common.Address {
+	var salt common.Hash
+	if f.Kind == KindV3 {
+		salt = crypto.Keccak256Hash(
+			common.LeftPadBytes(token0.Bytes(), 32),
+			common.LeftPadBytes(token1.Bytes(), 32),
+			common.LeftPadBytes(new(big.Int).SetUint64(uint64(fee)).Bytes(), 32),
+		)
+	} else {
+		salt = crypto.Keccak256Hash(token0.Bytes(), token1.Bytes())
+	}
+	return crypto.CreateAddress2(f.Deployer, salt, f.InitCodeHash.Bytes())
+}
+
+// DefaultFactories are the BSC factories known out of the box.
+var DefaultFactories = []Factory{
+	{
+		Name:         "PancakeSwap V2",
+		Kind:         KindV2,
+		Deployer:     common.HexToAddress("0xcA143Ce32Fe78f1f7019d7d551a6402fC5350c73"),
+		InitCodeHash: common.HexToHash("0x00fb7f630766e6a796048ea87d01acd3068e8ff67d078148a3fa3f4a84f69bd5"),
+	},
+	{
+		Name:         "Uniswap V2",
+		Kind:         KindV2,
+		Deployer:     common.HexToAddress("0x8909Dc15e40173Ff4699343b6eB8132c65e18eC6"),
+		InitCodeHash: common.HexToHash("0x96e8ac4277198ff8b6f785478aa9a39f403cb768dd02cbee326c3e7da348845f"),
+	},
+	{
+		Name:         "BiSwap",
+		Kind:         KindV2,
+		Deployer:     common.HexToAddress("0x858E3312ed3A876947EA49d572A7C42DE08af7EE"),
+		InitCodeHash: common.HexToHash("0xfea293c909d87cd4153593f077b76bb7e94340200f4ee84211ae8e4f9bd7ffdf"),
+	},
+	{
+		Name:         "ApeSwap",
+		Kind:         KindV2,
+		Deployer:     common.HexToAddress("0x0841BD0B734E4F5853f0dD8d7Ea041c241fb0Da6"),
+		InitCodeHash: common.HexToHash("0xf4ccce374816856d11f00e4069e7cada164065686fbef53c6167a63ec2fd8c5b"),
+	},
+	{
+		Name:         "PancakeSwap V3",
+		Kind:         KindV3,
+		Deployer:     common.HexToAddress("0x41ff9AA7e16B8B1a8a8dc4f0eFacd93D02d071c9"),
+		InitCodeHash: common.HexToHash("0x6ce8eb472fa82df5469c6ab6d485f17c3ad13c8cd7af59b3d4a8026c5ce0f7e2"),
+		FeeTiers:     []uint32{100, 500, 2500, 10000},
+	},
+	{
+		Name:         "Uniswap V3",
+		Kind:         KindV3,
+		Deployer:     common.HexToAddress("0xdB1d10011AD0Ff90774D0C6Bb92e5C5c8b4461F7"),
+		InitCodeHash: common.HexToHash("0xe34f199b19b2b4f47f68442619d555527d244f78a3297ea89325f843f87b8b54"),
+		FeeTiers:     []uint32{100, 500, 3000, 10000},
+	},
+}
+
+// Verifier checks pool authenticity against a table of factories. It is safe for concurrent use.
+type Verifier struct {
+	factories []Factory
+
+	mu       sync.RWMutex
+	verified map[common.Address]bool // verified caches genuine pools and allow-listed addresses
+}
+
+// NewVerifier creates a Verifier for the given factories.
+func NewVerifier(factories []Factory) *Verifier {
+	return &Verifier{
+		factories: factories,
+		verified:  make(map[common.Address]bool),
+	}
+}
+
+// Allow marks addresses as genuine pools without recomputing their address,
+// e.g. for forks whose factory is not in the table.
+func (v *Verifier) Allow(pools ...common.Address) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, pool := range pools {
+		v.verified[pool] = true
+	}
+}
+
+// Verify returns true if pool is the address a factory of the given kind deploys for
+// some pair of the candidate tokens (normally the tokens the pool sent or received).
+func (v *Verifier) Verify(kind Kind, pool common.Address, tokens []common.Address) bool {
+	v.mu.RLock()
+	verified := v.verified[pool]
+	v.mu.RUnlock()
+	if verified {
+		return true
+	}
+
+	for i := range tokens {
+		for j := i + 1; j < len(tokens); j++ {
+			if v.matches(kind, pool, tokens[i], tokens[j]) {
+				v.Allow(pool)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches checks a single token pair against all factories of a kind.
+func (v *Verifier) matches(kind Kind, pool, tokenA, tokenB common.Address) bool {
+	token0, token1 := tokenA, tokenB
+	if new(big.Int).SetBytes(token0.Bytes()).Cmp(new(big.Int).SetBytes(token1.Bytes())) > 0 {
+		token0, token1 = token1, token0
+	}
+
+	for _, factory := range v.factories {
+		if factory.Kind != kind {
+			continue
+		}
+		if kind == KindV2 {
+			if factory.PoolAddress(token0, token1, 0) == pool {
+				return true
+			}
+			continue
+		}
+		for _, fee := range factory.FeeTiers {
+			if factory.PoolAddress(token0, token1, fee) == pool {
+				return true
+			}
+		}
+	}
+	return false
+}
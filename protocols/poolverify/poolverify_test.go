@@ -0,0 +1,29 @@
+package poolverify
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVerifier(t *testing.T) {
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	cake := common.HexToAddress("0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82")
+	verifier := NewVerifier(DefaultFactories)
+
+	// PancakeSwap V2 USDT/WBNB
+	pair := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	if !verifier.Verify(KindV2, pair, []common.Address{wbnb, cake, usdt}) {
+		t.Errorf("Verify() PancakeSwap V2 USDT/WBNB = false, want true")
+	}
+	if verifier.Verify(KindV3, common.HexToAddress("0x00000000000000000000000000000000deadbeef"), []common.Address{usdt, wbnb}) {
+		t.Errorf("Verify() unknown address = true, want false")
+	}
+
+	fork := common.HexToAddress("0x00000000000000000000000000000000000f0f0f")
+	verifier.Allow(fork)
+	if !verifier.Verify(KindV2, fork, nil) {
+		t.Errorf("Verify() allow-listed pool = false, want true")
+	}
+}
@@ -0,0 +1,32 @@
+package protocols
+
+import (
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ObserveReserves feeds the Sync and Swap events of a single transaction into a V2 pool
+// state tracker, recording one snapshot per V2 swap immediately preceded by its pool's Sync.
+func ObserveReserves(state *uniswapv2.PoolState, txIndex int, logs []*types.Log) {
+	var previous *uniswapv2.Sync
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			previous = nil
+			continue
+		}
+
+		signature := log.Topics[0]
+		if signature == uniswapv2.SyncSignature {
+			if sync := uniswapv2.ParseSync(log); sync != nil {
+				state.ObserveSync(sync)
+				previous = sync
+				continue
+			}
+		} else if uniswapV2SwapSignatures[signature] {
+			if swap := uniswapv2.ParseSwap(log); swap != nil {
+				state.ObserveSwap(txIndex, previous, swap)
+			}
+		}
+		previous = nil
+	}
+}
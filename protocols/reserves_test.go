@@ -0,0 +1,37 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestObserveReserves(t *testing.T) {
+	var (
+		pool   = common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+		other  = common.HexToAddress("0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16")
+		trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+
+	state := uniswapv2.NewPoolState()
+	ObserveReserves(state, 0, []*types.Log{
+		logtest.V2Sync(pool, 1100000, 909091),
+		logtest.V2Swap(pool, trader, 100000, 0, 0, 90909),
+	})
+	if snapshots := state.TxSnapshots(pool, 0); len(snapshots) != 1 || snapshots[0].Reserve0Before.Int64() != 1000000 {
+		t.Errorf("ObserveReserves() snapshots = %+v, want one from 1000000", snapshots)
+	}
+
+	// A Sync of the pool followed by another pool's Sync does not describe the Swap after them.
+	ObserveReserves(state, 1, []*types.Log{
+		logtest.V2Sync(pool, 1000000, 1000000),
+		logtest.V2Sync(other, 5000, 5000),
+		logtest.V2Swap(pool, trader, 100000, 0, 0, 90909),
+	})
+	if snapshots := state.TxSnapshots(pool, 1); len(snapshots) != 0 {
+		t.Errorf("ObserveReserves() unpaired swap snapshots = %+v, want none", snapshots)
+	}
+}
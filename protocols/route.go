@@ -0,0 +1,93 @@
+package protocols
+
+import (
+	"github.com/48Club/bscexorcist/protocols/aggregator"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ParseRouteEvents extracts the aggregator route summaries (1inch, KyberSwap
+// MetaAggregation, OpenOcean) from the logs of a single transaction.
+// PancakeSwap Smart Router emits no summary event; its trades are only visible as per-pool swaps.
+// Native BNB is reported as WBNB.
+func ParseRouteEvents(logs []*types.Log) []*aggregator.Route {
+	return Config{}.ParseRouteEvents(logs)
+}
+
+// ParseRouteEvents extracts the aggregator route summaries like the package-level
+// ParseRouteEvents, reporting native BNB as the configured wrapped native token.
+func (c Config) ParseRouteEvents(logs []*types.Log) []*aggregator.Route {
+	var routes []*aggregator.Route
+	for _, log := range logs {
+		if route := aggregator.ParseRoute(log); route != nil {
+			route.SrcToken = tools.NormalizeToken(route.SrcToken, c.wrappedNative())
+			route.DstToken = tools.NormalizeToken(route.DstToken, c.wrappedNative())
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// RouteForPool returns the first aggregator route of a single transaction that trades
+// through the given pool, given the swaps parsed from the transaction. The pool's tokens are
+// resolved like ClassifyTransaction does; the route must reach the pool's input token from
+// its source token and the destination token from the pool's output token through the
+// swaps of the transaction. Returns nil if no route matches.
+func RouteForPool(logs []*types.Log, swaps []SwapEvent, pool common.Address, v4Pools *uniswapv4.PoolRegistry) *aggregator.Route {
+	return Config{}.RouteForPool(logs, swaps, pool, v4Pools)
+}
+
+// RouteForPool returns the route of a single transaction trading through the pool like the
+// package-level RouteForPool, counting native BNB as the configured wrapped native token.
+func (c Config) RouteForPool(logs []*types.Log, swaps []SwapEvent, pool common.Address, v4Pools *uniswapv4.PoolRegistry) *aggregator.Route {
+	routes := c.ParseRouteEvents(logs)
+	if len(routes) == 0 {
+		return nil
+	}
+
+	// hops maps each token to the tokens the transaction swapped it into.
+	hops := make(map[common.Address][]common.Address)
+	var poolHops [][2]common.Address
+	inflows, outflows := tokenFlows(logs)
+	for _, swap := range swaps {
+		tokenIn, tokenOut, ok := swapTokens(swap, v4Pools, c.wrappedNative(), inflows, outflows)
+		if !ok {
+			continue
+		}
+		hops[tokenIn] = append(hops[tokenIn], tokenOut)
+		if swap.PairID() == pool {
+			poolHops = append(poolHops, [2]common.Address{tokenIn, tokenOut})
+		}
+	}
+
+	for _, route := range routes {
+		for _, hop := range poolHops {
+			if swapsInto(hops, route.SrcToken, hop[0]) && swapsInto(hops, hop[1], route.DstToken) {
+				return route
+			}
+		}
+	}
+	return nil
+}
+
+// swapsInto returns true if from is to, or is swapped into it through a path of hops.
+func swapsInto(hops map[common.Address][]common.Address, from, to common.Address) bool {
+	seen := map[common.Address]bool{from: true}
+	queue := []common.Address{from}
+	for len(queue) > 0 {
+		token := queue[0]
+		queue = queue[1:]
+		if token == to {
+			return true
+		}
+		for _, next := range hops[token] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestRouteForPool(t *testing.T) {
+	var (
+		router = common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582")
+		first  = common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+		second = common.HexToAddress("0x4e7bd0A1fC0C4B2bF5C4b3c52E7E6E1cA3f1D9b2")
+		other  = common.HexToAddress("0x0eD7e52944161450477ee417DE9Cd3a859b14fD0")
+		usdt   = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		usdc   = common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d")
+		cake   = common.HexToAddress("0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82")
+		trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+	// 1inch Swapped(sender, srcToken, dstToken, dstReceiver, spentAmount, returnAmount)
+	routeLog := logtest.Log(router, "Swapped(address,address,address,address,uint256,uint256)", nil,
+		logtest.AddressWord(trader), logtest.AddressWord(usdt), logtest.AddressWord(usdc), logtest.AddressWord(trader),
+		logtest.Word(1000), logtest.Word(998))
+	// USDT -> WBNB -> USDC through two pools, and a CAKE purchase with USDT on a third.
+	logs := []*types.Log{
+		logtest.Transfer(usdt, router, first, 1000),
+		logtest.Transfer(tools.WBNB, first, second, 2),
+		logtest.V2Swap(first, router, 1000, 0, 0, 2),
+		logtest.Transfer(usdc, second, trader, 998),
+		logtest.V2Swap(second, router, 0, 2, 998, 0),
+		logtest.Transfer(usdt, trader, other, 500),
+		logtest.Transfer(cake, other, trader, 200),
+		logtest.V2Swap(other, trader, 500, 0, 0, 200),
+		routeLog,
+	}
+	swaps := ParseSwapEvents(logs)
+
+	for _, pool := range []common.Address{first, second} {
+		if route := RouteForPool(logs, swaps, pool, nil); route == nil || route.Router != router {
+			t.Errorf("RouteForPool() %s = %+v, want the 1inch route", pool.Hex(), route)
+		}
+	}
+	// The CAKE pool received the route's source token, but its CAKE does not lead to USDC.
+	if route := RouteForPool(logs, swaps, other, nil); route != nil {
+		t.Errorf("RouteForPool() pool off the route = %+v, want nil", route)
+	}
+}
+
+func TestRouteForPoolV4(t *testing.T) {
+	var (
+		router  = common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582")
+		manager = common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF")
+		poolID  = common.HexToHash("0xc012e144f83cd4c616704b5391205ad0dc19719ca9f89b739a67a9b1d5316f17")
+		usdt    = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		trader  = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+	// The PoolManager, not the pool, settles the swap of WBNB for USDT.
+	logs := []*types.Log{
+		logtest.Transfer(tools.WBNB, router, manager, 2),
+		logtest.Log(manager, "Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24)",
+			[]common.Hash{poolID, logtest.AddressTopic(router)},
+			logtest.Word(1180), logtest.Word(-2), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(-5), logtest.Word(500)),
+		logtest.Transfer(usdt, manager, trader, 1180),
+		logtest.Log(router, "Swapped(address,address,address,address,uint256,uint256)", nil,
+			logtest.AddressWord(trader), logtest.AddressWord(tools.NativeTokenPlaceholder), logtest.AddressWord(usdt), logtest.AddressWord(trader),
+			logtest.Word(2), logtest.Word(1180)),
+	}
+	swaps := ParseSwapEvents(logs)
+	if len(swaps) != 1 {
+		t.Fatalf("ParseSwapEvents() = %d swaps, want 1", len(swaps))
+	}
+
+	if route := RouteForPool(logs, swaps, swaps[0].PairID(), nil); route == nil || route.SrcToken != tools.WBNB || route.DstToken != usdt {
+		t.Errorf("RouteForPool() V4 pool = %+v, want the BNB -> USDT route", route)
+	}
+}
+
+func TestConfigWrappedNative(t *testing.T) {
+	var (
+		router = common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582")
+		usdt   = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		weth   = common.HexToAddress("0x4200000000000000000000000000000000000006")
+		trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+	logs := []*types.Log{logtest.Log(router, "Swapped(address,address,address,address,uint256,uint256)", nil,
+		logtest.AddressWord(trader), logtest.AddressWord(tools.NativeTokenPlaceholder), logtest.AddressWord(usdt),
+		logtest.AddressWord(trader), logtest.Word(2), logtest.Word(1180))}
+
+	if routes := ParseRouteEvents(logs); len(routes) != 1 || routes[0].SrcToken != tools.WBNB {
+		t.Errorf("ParseRouteEvents() = %+v, want native BNB reported as WBNB", routes)
+	}
+	config := Config{WrappedNative: weth}
+	if routes := config.ParseRouteEvents(logs); len(routes) != 1 || routes[0].SrcToken != weth {
+		t.Errorf("Config.ParseRouteEvents() = %+v, want the native currency reported as %s", routes, weth.Hex())
+	}
+}
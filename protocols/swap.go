@@ -5,10 +5,15 @@ import (
 	"math/big"
 
 	"github.com/48Club/bscexorcist/protocols/dodoswap"
-	"github.com/48Club/bscexorcist/protocols/fourmeme"
+	"github.com/48Club/bscexorcist/protocols/iziswap"
+	"github.com/48Club/bscexorcist/protocols/liquiditybook"
+	"github.com/48Club/bscexorcist/protocols/maverick"
+	"github.com/48Club/bscexorcist/protocols/pancakeinfinity"
 	"github.com/48Club/bscexorcist/protocols/uniswapv2"
 	"github.com/48Club/bscexorcist/protocols/uniswapv3"
 	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/48Club/bscexorcist/protocols/wombat"
+	"github.com/48Club/bscexorcist/protocols/woofi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -28,7 +33,9 @@ var (
 		common.HexToHash("0x606ecd02b3e3b4778f8e97b2e03351de14224efaa5fa64e62200afc9395c2499"): true,
 	}
 
-	// Uniswap V3 and compatible swap event signatures
+	// Uniswap V3 and compatible swap event signatures.
+	// KyberSwap Elastic pools emit Swap(sender, recipient, deltaQty0, deltaQty1, sqrtP, liquidity, currentTick),
+	// which has the same topic and layout as Uniswap V3 and is parsed here as well.
 	uniswapV3SwapSignatures = map[common.Hash]bool{
 		common.HexToHash("0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"): true,
 		common.HexToHash("0x19b47279256b2a23a1665c810c8d55a1758940ee09377d4f8d26497a3577dc83"): true,
@@ -38,44 +45,188 @@ var (
 	// Uniswap V4 and compatible swap event signature
 	uniswapV4SwapSignature = common.HexToHash("0x40e9cecb9f5f1f1c5b9c97dec2917b7ee92e57ba5563708daca94dd84ad7112f")
 
+	// Community-standard HookSwap event signature emitted by custom-curve V4 hooks
+	uniswapV4HookSwapSignature = common.HexToHash("0x365f10e9e7ce45d7acfd986c42e0b666f8af282e440e6dafc78c1f2b2f786760")
+
+	// PancakeSwap Infinity CLPoolManager and BinPoolManager swap event signatures
+	pancakeInfinityCLSwapSignature  = common.HexToHash("0x04206ad2b7c0f463bff3dd4f33c5735b0f2957a351e4f79763a4fa9e775dd237")
+	pancakeInfinityBinSwapSignature = common.HexToHash("0x3e8aae37f890eb1f9d63dd4d2062f3f0be757848a0f0760e4f3e53dad556e861")
+
 	// DODOSwap signature for swap events
 	dodoSwapSignature = common.HexToHash("0xc2c0245e056d5fb095f04cd6373bc770802ebd1e6c918eb78fdef843cdb37b0f")
 
-	fourMemeSwapSignatures = map[common.Hash]bool{
-		common.HexToHash("0x7db52723a3b2cdd6164364b3b766e65e540d7be48ffa89582956d8eaebe62942"): true,
-		common.HexToHash("0x0a5575b3648bae2210cee56bf33254cc1ddfbc7bf637c0af2ac18b14fb1bae19"): true,
+	// DODO V1 classic pool SellBaseToken and BuyBaseToken event signatures
+	dodoV1SwapSignatures = map[common.Hash]bool{
+		common.HexToHash("0xd8648b6ac54162763c86fd54bf2005af8ecd2f9cb273a5775921fd7f91e17b2d"): true,
+		common.HexToHash("0xe93ad76094f247c0dafc1c61adc2187de1ac2738f7a3b49cb20b2263420251a3"): true,
 	}
+
+	// Liquidity Book (bin-based AMM) swap event signature
+	liquidityBookSwapSignature = common.HexToHash("0xad7d6f97abf51ce18e17a38f4d70e975be9c0708474987bb3e26ad21bd93ca70")
+
+	// iZiSwap swap event signature
+	iZiSwapSwapSignature = common.HexToHash("0x0fe977d619f8172f7fdbe8bb8928ef80952817d96936509f67d66346bc4cd10f")
+
+	// Maverick V2 PoolSwap event signature
+	maverickV2SwapSignature = common.HexToHash("0x103ed084e94a44c8f5f6ba8e3011507c41063177e29949083c439777d8d63f60")
+
+	// WOOFi WooPPV2 WooSwap event signature
+	wooSwapSignature = common.HexToHash("0x0e8e403c2d36126272b08c75823e988381d9dc47f2f0a9a080d95f891d95c469")
+
+	// Wombat pool swap event signature
+	wombatSwapSignature = common.HexToHash("0x54787c404bb33c88e86f4baf88183a3b0141d0a848e6a9f7a13b66ae3a9b73d1")
 )
 
 // ParseSwapEvents extracts swap events from a slice of logs for a single transaction.
 // Returns a slice of SwapEvent for all recognized swap events in the logs.
+// V4 hook swap deltas are combined with the PoolManager swap of the same pool.
 func ParseSwapEvents(logs []*types.Log) []SwapEvent {
-	var swaps []SwapEvent
+	return Config{}.ParseSwapEvents(logs)
+}
+
+// ParseSwapEvents extracts swap events like the package-level ParseSwapEvents, also
+// decoding the events of the configured hooks and launchpads.
+func (c Config) ParseSwapEvents(logs []*types.Log) []SwapEvent {
+	swaps, _ := c.parseSwapEvents(logs)
+	return swaps
+}
+
+// parseSwapEvents implements ParseSwapEvents and also returns the addresses of the
+// contracts whose logs were decoded as swaps.
+func (c Config) parseSwapEvents(logs []*types.Log) ([]SwapEvent, map[common.Address]bool) {
+	var (
+		swaps     []SwapEvent
+		hookSwaps []positionedHookSwap
+		decoded   = make(map[common.Address]bool)
+	)
 
 	for _, log := range logs {
 		if len(log.Topics) == 0 {
 			continue
 		}
 
+		if hookSwap := c.HookAdapters.ParseLog(log); hookSwap != nil {
+			hookSwaps = append(hookSwaps, positionedHookSwap{hookSwap, len(swaps)})
+			decoded[log.Address] = true
+			continue
+		}
+
 		signature := log.Topics[0]
 
 		var swap SwapEvent
 		if uniswapV2SwapSignatures[signature] {
-			swap = uniswapv2.ParseSwap(log)
+			swap = asSwapEvent(uniswapv2.ParseSwap(log))
 		} else if uniswapV3SwapSignatures[signature] {
-			swap = uniswapv3.ParseSwap(log)
+			swap = asSwapEvent(uniswapv3.ParseSwap(log))
 		} else if signature == uniswapV4SwapSignature {
-			swap = uniswapv4.ParseSwap(log)
+			swap = asSwapEvent(uniswapv4.ParseSwap(log))
+		} else if signature == uniswapV4HookSwapSignature {
+			if hookSwap := uniswapv4.ParseHookSwap(log); hookSwap != nil {
+				hookSwaps = append(hookSwaps, positionedHookSwap{hookSwap, len(swaps)})
+				decoded[log.Address] = true
+			}
+		} else if signature == pancakeInfinityCLSwapSignature {
+			swap = asSwapEvent(pancakeinfinity.ParseCLSwap(log))
+		} else if signature == pancakeInfinityBinSwapSignature {
+			swap = asSwapEvent(pancakeinfinity.ParseBinSwap(log))
 		} else if signature == dodoSwapSignature {
-			swap = dodoswap.ParseSwap(log)
-		} else if fourMemeSwapSignatures[signature] {
-			swap = fourmeme.ParseSwap(log)
+			swap = asSwapEvent(dodoswap.ParseSwap(log))
+		} else if dodoV1SwapSignatures[signature] {
+			if v1Swap := dodoswap.ParseV1Swap(log); v1Swap != nil {
+				v1Swap.ResolveTokens(logs)
+				swap = v1Swap
+			}
+		} else if signature == liquidityBookSwapSignature {
+			swap = asSwapEvent(liquiditybook.ParseSwap(log))
+		} else if signature == iZiSwapSwapSignature {
+			swap = asSwapEvent(iziswap.ParseSwap(log))
+		} else if signature == maverickV2SwapSignature {
+			swap = asSwapEvent(maverick.ParseSwap(log))
+		} else if signature == wooSwapSignature {
+			swap = asSwapEvent(woofi.ParseSwap(log))
+		} else if signature == wombatSwapSignature {
+			swap = asSwapEvent(wombat.ParseSwap(log))
+		} else if _, ok := c.Launchpads.Lookup(signature); ok {
+			swap = asSwapEvent(c.Launchpads.ParseSwap(log))
 		}
 
 		if swap != nil {
 			swaps = append(swaps, swap)
+			decoded[log.Address] = true
 		}
 	}
 
-	return swaps
+	if len(hookSwaps) > 0 {
+		swaps = combineHookSwaps(swaps, hookSwaps)
+	}
+
+	return swaps, decoded
+}
+
+// asSwapEvent converts a parser result to a SwapEvent, keeping a nil result a nil
+// interface so that malformed logs are skipped instead of dereferenced.
+func asSwapEvent[T any, P interface {
+	*T
+	SwapEvent
+}](swap P) SwapEvent {
+	if swap == nil {
+		return nil
+	}
+	return swap
+}
+
+// positionedHookSwap is a hook swap together with the number of swaps parsed before it.
+type positionedHookSwap struct {
+	swap     *uniswapv4.HookSwap
+	position int
+}
+
+// combineHookSwaps folds hook deltas into the first V4 swap of the same pool and drops
+// the pool's later V4 swaps, leaving a single swap per hooked pool. Pools settled by their
+// hook alone take the position of their first hook swap.
+func combineHookSwaps(swaps []SwapEvent, hookSwaps []positionedHookSwap) []SwapEvent {
+	hooked := make(map[common.Hash][]*uniswapv4.HookSwap)
+	var order []positionedHookSwap // first hook swap of each pool
+	for _, hookSwap := range hookSwaps {
+		poolID := hookSwap.swap.PoolID
+		if _, ok := hooked[poolID]; !ok {
+			order = append(order, hookSwap)
+		}
+		hooked[poolID] = append(hooked[poolID], hookSwap.swap)
+	}
+
+	poolSwaps := make(map[common.Hash][]*uniswapv4.V4Swap)
+	for _, swap := range swaps {
+		if v4Swap, ok := swap.(*uniswapv4.V4Swap); ok && hooked[v4Swap.PoolID()] != nil {
+			poolSwaps[v4Swap.PoolID()] = append(poolSwaps[v4Swap.PoolID()], v4Swap)
+		}
+	}
+
+	var combined []SwapEvent
+	// Hooks that settled the swap entirely without a PoolManager Swap event.
+	appendHookOnly := func(position int) {
+		for len(order) > 0 && order[0].position <= position {
+			poolID := order[0].swap.PoolID
+			if _, ok := poolSwaps[poolID]; !ok {
+				combined = append(combined, uniswapv4.CombineHookSwaps(nil, hooked[poolID]))
+			}
+			order = order[1:]
+		}
+	}
+
+	for i, swap := range swaps {
+		appendHookOnly(i)
+		v4Swap, ok := swap.(*uniswapv4.V4Swap)
+		if !ok || hooked[v4Swap.PoolID()] == nil {
+			combined = append(combined, swap)
+			continue
+		}
+		if pending := poolSwaps[v4Swap.PoolID()]; pending != nil {
+			combined = append(combined, uniswapv4.CombineHookSwaps(pending, hooked[v4Swap.PoolID()]))
+			poolSwaps[v4Swap.PoolID()] = nil
+		}
+	}
+	appendHookOnly(len(swaps))
+
+	return combined
 }
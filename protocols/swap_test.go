@@ -0,0 +1,156 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/bondingcurve"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/48Club/bscexorcist/protocols/uniswapv4"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseSwapEvents(t *testing.T) {
+	var (
+		pool   = common.HexToAddress("0x4e7bd0A1fC0C4B2bF5C4b3c52E7E6E1cA3f1D9b2")
+		usdt   = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		usdc   = common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d")
+		trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+		actors = []common.Hash{logtest.AddressTopic(trader), logtest.AddressTopic(trader)}
+	)
+
+	tests := []struct {
+		name     string
+		log      *types.Log
+		wantPair common.Address
+	}{
+		{
+			name:     "uniswap v2",
+			log:      logtest.V2Swap(pool, trader, 1000, 0, 0, 3),
+			wantPair: pool,
+		},
+		{
+			name: "liquidity book",
+			log: logtest.Log(pool, "Swap(address,address,uint24,bytes32,bytes32,uint24,bytes32,bytes32)", actors,
+				logtest.Word(8388608), logtest.Word(1000), logtest.Word(0), logtest.Word(0), logtest.Word(0), logtest.Word(0)),
+			wantPair: pool,
+		},
+		{
+			name: "dodo v2",
+			log: logtest.Log(pool, "DODOSwap(address,address,uint256,uint256,address,address)", nil,
+				logtest.AddressWord(usdt), logtest.AddressWord(usdc), logtest.Word(1000), logtest.Word(998), logtest.AddressWord(trader), logtest.AddressWord(trader)),
+			wantPair: pool,
+		},
+		{
+			name:     "dodo v1",
+			log:      logtest.Log(pool, "SellBaseToken(address,uint256,uint256)", actors[:1], logtest.Word(10), logtest.Word(3000)),
+			wantPair: pool,
+		},
+		{
+			name: "wombat",
+			log: logtest.Log(pool, "Swap(address,address,address,uint256,uint256,address)", actors,
+				logtest.AddressWord(usdt), logtest.AddressWord(usdc), logtest.Word(1000), logtest.Word(999)),
+			wantPair: tools.VirtualPairID(pool, usdt, usdc),
+		},
+		{
+			name: "bonding curve",
+			log: logtest.Log(pool, "TokenPurchase(address,address,uint256,uint256)", nil,
+				logtest.AddressWord(usdt), logtest.AddressWord(trader), logtest.Word(1000), logtest.Word(1)),
+			wantPair: usdt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swaps := ParseSwapEvents([]*types.Log{tt.log})
+			if len(swaps) != 1 {
+				t.Fatalf("ParseSwapEvents() = %d swaps, want 1", len(swaps))
+			}
+			if swaps[0].PairID() != tt.wantPair {
+				t.Errorf("ParseSwapEvents() pair = %s, want %s", swaps[0].PairID().Hex(), tt.wantPair.Hex())
+			}
+		})
+	}
+
+	// A recognized event too short to decode is skipped rather than returned as a nil swap.
+	truncated := logtest.Log(pool, "Swap(address,address,uint24,bytes32,bytes32,uint24,bytes32,bytes32)", actors, logtest.Word(8388608))
+	if swaps := ParseSwapEvents([]*types.Log{truncated}); len(swaps) != 0 {
+		t.Errorf("ParseSwapEvents() truncated log = %v, want none", swaps)
+	}
+}
+
+func TestParseSwapEventsHookOnlyOrder(t *testing.T) {
+	var (
+		first  = common.HexToAddress("0x4e7bd0A1fC0C4B2bF5C4b3c52E7E6E1cA3f1D9b2")
+		last   = common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+		hook   = common.HexToAddress("0x1e9c64cad39DDD36fB808E004067Cffc710EB71D")
+		poolID = common.HexToHash("0xc012e144f83cd4c616704b5391205ad0dc19719ca9f89b739a67a9b1d5316f17")
+		trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+
+	// A multi-hop route through a pool whose hook settles the swap without a PoolManager Swap event.
+	swaps := ParseSwapEvents([]*types.Log{
+		logtest.V2Swap(first, trader, 1000, 0, 0, 3),
+		logtest.Log(hook, "HookSwap(bytes32,address,int128,int128,uint128,uint128)",
+			[]common.Hash{poolID, logtest.AddressTopic(trader)}, logtest.Word(-3), logtest.Word(2), logtest.Word(0), logtest.Word(0)),
+		logtest.V2Swap(last, trader, 2, 0, 0, 990),
+	})
+	if len(swaps) != 3 {
+		t.Fatalf("ParseSwapEvents() = %d swaps, want 3", len(swaps))
+	}
+	if swaps[0].PairID() != first || swaps[2].PairID() != last {
+		t.Errorf("ParseSwapEvents() pairs = %s, %s, %s, want the hook swap in the middle", swaps[0].PairID().Hex(), swaps[1].PairID().Hex(), swaps[2].PairID().Hex())
+	}
+}
+
+// curveAdapter decodes the Swapped(bytes32,int128,int128) event of a test custom-curve hook.
+type curveAdapter struct{}
+
+func (curveAdapter) ParseHookSwap(log *types.Log) *uniswapv4.HookSwap {
+	if len(log.Topics) != 2 || len(log.Data) < 64 {
+		return nil
+	}
+	return &uniswapv4.HookSwap{
+		PoolID:  log.Topics[1],
+		Hook:    log.Address,
+		Amount0: tools.DecodeSignedInt256(log.Data[:32]),
+		Amount1: tools.DecodeSignedInt256(log.Data[32:64]),
+	}
+}
+
+func TestConfigHookAdapters(t *testing.T) {
+	var (
+		hook   = common.HexToAddress("0x1e9c64cad39DDD36fB808E004067Cffc710EB71D")
+		poolID = common.HexToHash("0xc012e144f83cd4c616704b5391205ad0dc19719ca9f89b739a67a9b1d5316f17")
+	)
+	logs := []*types.Log{logtest.Log(hook, "Swapped(bytes32,int128,int128)", []common.Hash{poolID}, logtest.Word(-1000), logtest.Word(990))}
+
+	config := Config{HookAdapters: uniswapv4.HookAdapters{hook: curveAdapter{}}}
+	swaps := config.ParseSwapEvents(logs)
+	if len(swaps) != 1 || !swaps[0].IsToken0To1() || swaps[0].AmountIn().Int64() != 1000 || swaps[0].AmountOut().Int64() != 990 {
+		t.Fatalf("Config.ParseSwapEvents() = %+v, want one 1000 -> 990 token0 -> token1 swap", swaps)
+	}
+	if swaps := ParseSwapEvents(logs); len(swaps) != 0 {
+		t.Errorf("ParseSwapEvents() without adapter = %+v, want none", swaps)
+	}
+}
+
+func TestConfigLaunchpads(t *testing.T) {
+	var (
+		portal = common.HexToAddress("0x5c952063c7fc8610FFDB798152D69F0B9550762b")
+		token  = common.HexToAddress("0x4e3a5f2c1d9b7e8a6c0f1b2d3e4f5a6b7c8d4444")
+		trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+	logs := []*types.Log{logtest.Log(portal, "Bought(address,address,uint256,uint256)", nil,
+		logtest.AddressWord(token), logtest.AddressWord(trader), logtest.Word(5000), logtest.Word(10))}
+
+	config := Config{Launchpads: bondingcurve.MustNewRegistry(bondingcurve.Launchpad{
+		Name: "test", Event: "Bought(address,address,uint256,uint256)", Buy: true, TokenWord: 0, TokenAmountWord: 2, QuoteAmountWord: 3,
+	})}
+	if swaps := config.ParseSwapEvents(logs); len(swaps) != 1 || swaps[0].PairID() != token || !swaps[0].IsToken0To1() {
+		t.Errorf("Config.ParseSwapEvents() = %+v, want a purchase of the token", swaps)
+	}
+	if swaps := ParseSwapEvents(logs); len(swaps) != 0 {
+		t.Errorf("ParseSwapEvents() unregistered launchpad = %+v, want none", swaps)
+	}
+}
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// NativeToken is the zero-address currency used for native BNB by V4/Infinity pools.
+	NativeToken = common.Address{}
+
+	// NativeTokenPlaceholder is the 0xEeee...EEeE address used for native BNB by routers and aggregators.
+	NativeTokenPlaceholder = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+	// WBNB is the wrapped native token of BSC.
+	WBNB = common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+)
+
+// NormalizeToken maps the native currency placeholders to the wrapped native token
+// (WBNB on BSC), so that native BNB and WBNB are treated as one asset. Other tokens are
+// returned unchanged.
+func NormalizeToken(token, wrappedNative common.Address) common.Address {
+	if token == NativeToken || token == NativeTokenPlaceholder {
+		return wrappedNative
+	}
+	return token
+}
+
+// IsSortedPair returns true if tokenA sorts below tokenB, i.e. tokenA is token0 of the pair.
+func IsSortedPair(tokenA, tokenB common.Address) bool {
+	return bytes.Compare(tokenA.Bytes(), tokenB.Bytes()) < 0
+}
@@ -0,0 +1,11 @@
+package tools
+
+import "math/big"
+
+// DecodePackedUint128 splits a 32-byte word holding two packed uint128 values.
+// Returns the lower 128 bits first and the upper 128 bits second.
+func DecodePackedUint128(data []byte) (low, high *big.Int) {
+	high = new(big.Int).SetBytes(data[:16])
+	low = new(big.Int).SetBytes(data[16:32])
+	return low, high
+}
@@ -0,0 +1,21 @@
+package tools
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SortTokens returns the two tokens in ascending address order.
+func SortTokens(tokenA, tokenB common.Address) (token0, token1 common.Address) {
+	if IsSortedPair(tokenA, tokenB) {
+		return tokenA, tokenB
+	}
+	return tokenB, tokenA
+}
+
+// VirtualPairID returns a pseudo pool address for a token pair traded on a
+// single-contract, multi-token venue, derived from the venue and the sorted tokens.
+func VirtualPairID(venue, tokenA, tokenB common.Address) common.Address {
+	token0, token1 := SortTokens(tokenA, tokenB)
+	return common.BytesToAddress(crypto.Keccak256(venue.Bytes(), token0.Bytes(), token1.Bytes()))
+}
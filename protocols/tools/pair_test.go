@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVirtualPairID(t *testing.T) {
+	venue := common.HexToAddress("0x59dE3B49314Bf5067719364A00E6a4e0aaa3d9Ac")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+
+	if VirtualPairID(venue, usdt, WBNB) != VirtualPairID(venue, WBNB, usdt) {
+		t.Error("VirtualPairID() depends on the token order")
+	}
+	if VirtualPairID(venue, usdt, WBNB) == VirtualPairID(common.Address{}, usdt, WBNB) {
+		t.Error("VirtualPairID() does not depend on the venue")
+	}
+}
+
+func TestNormalizeToken(t *testing.T) {
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	weth := common.HexToAddress("0x4200000000000000000000000000000000000006")
+
+	if NormalizeToken(NativeToken, WBNB) != WBNB || NormalizeToken(NativeTokenPlaceholder, WBNB) != WBNB {
+		t.Error("NormalizeToken() native placeholder is not WBNB")
+	}
+	if NormalizeToken(NativeTokenPlaceholder, weth) != weth {
+		t.Error("NormalizeToken() ignores the wrapped native token of the chain")
+	}
+	if NormalizeToken(usdt, WBNB) != usdt {
+		t.Error("NormalizeToken() changed an ERC20 token")
+	}
+}
+
+func TestDecodePackedUint128(t *testing.T) {
+	word := common.BigToHash(new(big.Int).Add(new(big.Int).Lsh(big.NewInt(7), 128), big.NewInt(5))).Bytes()
+	if low, high := DecodePackedUint128(word); low.Int64() != 5 || high.Int64() != 7 {
+		t.Errorf("DecodePackedUint128() = %v, %v, want 5, 7", low, high)
+	}
+}
@@ -0,0 +1,173 @@
+// Package transferflow infers swaps from ERC20 Transfer events, as a fallback for
+// DEXes whose swap events are not recognized.
+package transferflow
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/48Club/bscexorcist/protocols/wbnb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransferSignature is the ERC20 Transfer(address,address,uint256) event signature.
+var TransferSignature = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// Transfer represents an ERC20 Transfer event.
+type Transfer struct {
+	Token common.Address
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// ParseTransfer parses an ERC20 Transfer log into a Transfer struct.
+// Returns nil if the log is not a valid ERC20 transfer (ERC721 transfers index the value and are skipped).
+func ParseTransfer(log *types.Log) *Transfer {
+	if len(log.Topics) != 3 || log.Topics[0] != TransferSignature || len(log.Data) < 32 {
+		return nil
+	}
+
+	return &Transfer{
+		Token: log.Address,
+		From:  common.BytesToAddress(log.Topics[1].Bytes()),
+		To:    common.BytesToAddress(log.Topics[2].Bytes()),
+		Value: new(big.Int).SetBytes(log.Data[:32]),
+	}
+}
+
+// ParseNFTTransfer parses an ERC721 Transfer log, whose token id is indexed, into a Transfer
+// struct holding the token id as Value. Returns nil if the log is not a valid ERC721 transfer.
+func ParseNFTTransfer(log *types.Log) *Transfer {
+	if len(log.Topics) != 4 || log.Topics[0] != TransferSignature {
+		return nil
+	}
+
+	return &Transfer{
+		Token: log.Address,
+		From:  common.BytesToAddress(log.Topics[1].Bytes()),
+		To:    common.BytesToAddress(log.Topics[2].Bytes()),
+		Value: new(big.Int).SetBytes(log.Topics[3].Bytes()),
+	}
+}
+
+// InferredSwap implements SwapEvent for a swap inferred from token flows: a contract
+// that received one token and sent out another within the same transaction.
+type InferredSwap struct {
+	pool      common.Address
+	tokenIn   common.Address
+	tokenOut  common.Address
+	amountIn  *big.Int
+	amountOut *big.Int
+}
+
+// PairID returns the address of the contract that received and sent the tokens.
+func (s *InferredSwap) PairID() common.Address {
+	return s.pool
+}
+
+// IsToken0To1 returns true if the swap direction is token0 -> token1, where token0 is
+// the lower token address.
+func (s *InferredSwap) IsToken0To1() bool {
+	return tools.IsSortedPair(s.tokenIn, s.tokenOut)
+}
+
+// AmountIn returns the net amount of tokenIn received by the contract.
+func (s *InferredSwap) AmountIn() *big.Int {
+	return new(big.Int).Set(s.amountIn)
+}
+
+// AmountOut returns the net amount of tokenOut sent by the contract.
+func (s *InferredSwap) AmountOut() *big.Int {
+	return new(big.Int).Set(s.amountOut)
+}
+
+// TokenIn returns the token received by the contract.
+func (s *InferredSwap) TokenIn() common.Address {
+	return s.tokenIn
+}
+
+// TokenOut returns the token sent by the contract.
+func (s *InferredSwap) TokenOut() common.Address {
+	return s.tokenOut
+}
+
+// Heuristic reports that the swap was inferred rather than decoded from a swap event.
+func (s *InferredSwap) Heuristic() bool {
+	return true
+}
+
+// InferSwaps infers swaps from the ERC20 transfers of a single transaction.
+// A contract becomes an inferred pool if it emitted a log of its own, received a net
+// amount of exactly one token and sent a net amount of exactly one other token.
+// Deposit and Withdrawal events of the wrapped native token (tools.WBNB on BSC) count as
+// flows of the wrapped token for the wrapping account.
+// Addresses for which skip returns true (e.g. pools whose swaps are already decoded) are ignored.
+func InferSwaps(logs []*types.Log, wrappedNative common.Address, skip func(common.Address) bool) []*InferredSwap {
+	emitters := make(map[common.Address]bool)
+	flows := make(map[common.Address]map[common.Address]*big.Int)
+	var order []common.Address
+
+	addFlow := func(account, token common.Address, value *big.Int) {
+		if account == (common.Address{}) {
+			return
+		}
+		if flows[account] == nil {
+			flows[account] = make(map[common.Address]*big.Int)
+			order = append(order, account)
+		}
+		if flows[account][token] == nil {
+			flows[account][token] = new(big.Int)
+		}
+		flows[account][token].Add(flows[account][token], value)
+	}
+
+	for _, log := range logs {
+		if wrap := wbnb.ParseWrap(log, wrappedNative); wrap != nil {
+			addFlow(wrap.Account, log.Address, wrap.Delta())
+			continue
+		}
+		transfer := ParseTransfer(log)
+		if transfer == nil {
+			emitters[log.Address] = true
+			continue
+		}
+		addFlow(transfer.To, transfer.Token, transfer.Value)
+		addFlow(transfer.From, transfer.Token, new(big.Int).Neg(transfer.Value))
+	}
+
+	var swaps []*InferredSwap
+	for _, account := range order {
+		if !emitters[account] || (skip != nil && skip(account)) {
+			continue
+		}
+		if swap := inferSwap(account, flows[account]); swap != nil {
+			swaps = append(swaps, swap)
+		}
+	}
+	return swaps
+}
+
+// inferSwap returns a swap if the net flows of account are exactly one token in and one token out.
+func inferSwap(account common.Address, flows map[common.Address]*big.Int) *InferredSwap {
+	swap := &InferredSwap{pool: account}
+	for token, net := range flows {
+		switch net.Sign() {
+		case 1:
+			if swap.amountIn != nil {
+				return nil
+			}
+			swap.tokenIn, swap.amountIn = token, new(big.Int).Set(net)
+		case -1:
+			if swap.amountOut != nil {
+				return nil
+			}
+			swap.tokenOut, swap.amountOut = token, new(big.Int).Neg(net)
+		}
+	}
+	if swap.amountIn == nil || swap.amountOut == nil {
+		return nil
+	}
+	return swap
+}
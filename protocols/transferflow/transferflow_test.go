@@ -0,0 +1,58 @@
+package transferflow
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	amm    = common.HexToAddress("0x7d8e5cE1d2D4b5A4F0d3E3C5a8b1E4a9C0f2D3b4")
+	usdt   = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	token  = common.HexToAddress("0x4e3a5f2c1d9b7e8a6c0f1b2d3e4f5a6b7c8d4444")
+	trader = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+)
+
+func TestParseTransfer(t *testing.T) {
+	transfer := ParseTransfer(logtest.Transfer(usdt, trader, amm, 1000))
+	if transfer == nil || transfer.Token != usdt || transfer.From != trader || transfer.To != amm || transfer.Value.Int64() != 1000 {
+		t.Errorf("ParseTransfer() = %+v", transfer)
+	}
+
+	// ERC721 transfers index the token id.
+	nft := logtest.Log(token, "Transfer(address,address,uint256)", []common.Hash{logtest.AddressTopic(trader), logtest.AddressTopic(amm), logtest.IntTopic(7)})
+	if transfer := ParseTransfer(nft); transfer != nil {
+		t.Errorf("ParseTransfer() ERC721 transfer = %+v, want nil", transfer)
+	}
+}
+
+func TestInferSwaps(t *testing.T) {
+	logs := []*types.Log{
+		logtest.Transfer(usdt, trader, amm, 1000),
+		logtest.Transfer(token, amm, trader, 500),
+		logtest.Log(amm, "Traded(uint256)", nil, logtest.Word(1)),
+	}
+
+	swaps := InferSwaps(logs, tools.WBNB, nil)
+	if len(swaps) != 1 {
+		t.Fatalf("InferSwaps() = %d swaps, want 1", len(swaps))
+	}
+	swap := swaps[0]
+	if swap.PairID() != amm || swap.TokenIn() != usdt || swap.TokenOut() != token || !swap.Heuristic() {
+		t.Errorf("InferSwaps() = pool %s, %s -> %s", swap.PairID().Hex(), swap.TokenIn().Hex(), swap.TokenOut().Hex())
+	}
+	if swap.AmountIn().Int64() != 1000 || swap.AmountOut().Int64() != 500 || swap.IsToken0To1() != tools.IsSortedPair(usdt, token) {
+		t.Errorf("InferSwaps() = 0 to 1 %v, %v -> %v", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+
+	if swaps := InferSwaps(logs, tools.WBNB, func(pool common.Address) bool { return pool == amm }); len(swaps) != 0 {
+		t.Errorf("InferSwaps() skipped pool = %+v, want none", swaps)
+	}
+	// Without a log of its own the recipient is an account, not a pool.
+	if swaps := InferSwaps(logs[:2], tools.WBNB, nil); len(swaps) != 0 {
+		t.Errorf("InferSwaps() without emitter = %+v, want none", swaps)
+	}
+}
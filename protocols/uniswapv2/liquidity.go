@@ -0,0 +1,99 @@
+package uniswapv2
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LiquidityChange is a Mint or Burn of a V2 pair's liquidity.
+type LiquidityChange struct {
+	pool     common.Address
+	provider common.Address
+	amount0  *big.Int
+	amount1  *big.Int
+	add      bool
+}
+
+// PairID returns the pool address.
+func (l *LiquidityChange) PairID() common.Address {
+	return l.pool
+}
+
+// Provider returns the liquidity provider: the recipient of the withdrawn tokens for a Burn,
+// and the caller of mint (usually a router) for a Mint until SetProvider records the LP token recipient.
+func (l *LiquidityChange) Provider() common.Address {
+	return l.provider
+}
+
+// SetProvider overrides the liquidity provider, e.g. with the recipient of the minted LP tokens.
+func (l *LiquidityChange) SetProvider(provider common.Address) {
+	l.provider = provider
+}
+
+// PositionID returns the zero hash, V2 liquidity is fungible and covers the full range.
+func (l *LiquidityChange) PositionID() common.Hash {
+	return common.Hash{}
+}
+
+// IsAdd returns true for a Mint and false for a Burn.
+func (l *LiquidityChange) IsAdd() bool {
+	return l.add
+}
+
+// Amount0 returns the token0 amount deposited or withdrawn.
+func (l *LiquidityChange) Amount0() *big.Int {
+	return l.amount0
+}
+
+// Amount1 returns the token1 amount deposited or withdrawn.
+func (l *LiquidityChange) Amount1() *big.Int {
+	return l.amount1
+}
+
+// ParseMint parses a Uniswap V2 Mint log into a LiquidityChange.
+// Returns nil if the log is not a valid mint event.
+func ParseMint(log *types.Log) *LiquidityChange {
+	// Topics: signature, sender
+	// Data layout: amount0, amount1
+	if len(log.Topics) != 2 || len(log.Data) < 64 {
+		return nil
+	}
+
+	return &LiquidityChange{
+		pool:     log.Address,
+		provider: common.BytesToAddress(log.Topics[1].Bytes()),
+		amount0:  new(big.Int).SetBytes(log.Data[:32]),
+		amount1:  new(big.Int).SetBytes(log.Data[32:64]),
+		add:      true,
+	}
+}
+
+// ParseBurn parses a Uniswap V2 Burn log into a LiquidityChange.
+// Returns nil if the log is not a valid burn event.
+func ParseBurn(log *types.Log) *LiquidityChange {
+	// Topics: signature, sender, to
+	// Data layout: amount0, amount1
+	if len(log.Topics) != 3 || len(log.Data) < 64 {
+		return nil
+	}
+
+	return &LiquidityChange{
+		pool:     log.Address,
+		provider: common.BytesToAddress(log.Topics[2].Bytes()),
+		amount0:  new(big.Int).SetBytes(log.Data[:32]),
+		amount1:  new(big.Int).SetBytes(log.Data[32:64]),
+	}
+}
+
+// QuoteOut returns the output of a constant-product swap of amountIn against the given
+// reserves, ignoring the pool fee. Returns nil if the reserves are empty.
+func QuoteOut(amountIn, reserveIn, reserveOut *big.Int) *big.Int {
+	denominator := new(big.Int).Add(reserveIn, amountIn)
+	if denominator.Sign() == 0 {
+		return nil
+	}
+	out := new(big.Int).Mul(amountIn, reserveOut)
+	return out.Quo(out, denominator)
+}
@@ -0,0 +1,40 @@
+package uniswapv2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseLiquidityChange(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	router := common.HexToAddress("0x10ED43C718714eb63d5aA57B78B54704E256024E")
+	provider := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+
+	mint := ParseMint(logtest.Log(pool, "Mint(address,uint256,uint256)", []common.Hash{logtest.AddressTopic(router)},
+		logtest.Word(500), logtest.Word(400)))
+	if mint == nil || !mint.IsAdd() || mint.PairID() != pool || mint.Provider() != router || mint.Amount0().Int64() != 500 || mint.Amount1().Int64() != 400 {
+		t.Fatalf("ParseMint() = %+v", mint)
+	}
+	mint.SetProvider(provider)
+	if mint.Provider() != provider {
+		t.Errorf("SetProvider() provider = %s, want %s", mint.Provider().Hex(), provider.Hex())
+	}
+
+	burn := ParseBurn(logtest.Log(pool, "Burn(address,uint256,uint256,address)",
+		[]common.Hash{logtest.AddressTopic(router), logtest.AddressTopic(provider)}, logtest.Word(500), logtest.Word(400)))
+	if burn == nil || burn.IsAdd() || burn.Provider() != provider || burn.PositionID() != (common.Hash{}) {
+		t.Errorf("ParseBurn() = %+v", burn)
+	}
+}
+
+func TestQuoteOut(t *testing.T) {
+	if out := QuoteOut(big.NewInt(10000), big.NewInt(500000), big.NewInt(500000)); out.Int64() != 9803 {
+		t.Errorf("QuoteOut() = %v, want 9803", out)
+	}
+	if out := QuoteOut(big.NewInt(0), big.NewInt(0), big.NewInt(0)); out != nil {
+		t.Errorf("QuoteOut() empty pool = %v, want nil", out)
+	}
+}
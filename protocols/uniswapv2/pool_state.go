@@ -0,0 +1,142 @@
+package uniswapv2
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SyncSignature is the Uniswap V2 and compatible Sync(uint112,uint112) event signature.
+var SyncSignature = common.HexToHash("0x1c411e9a96e071241c2f21f7726b17ae89e3cab4c78be50e062b03a9fffbbad1")
+
+// Sync represents a Uniswap V2 Sync event, emitted with the pair's reserves after every
+// swap, mint and burn. Within a swap the pair emits Sync right before Swap.
+type Sync struct {
+	Pool     common.Address
+	Reserve0 *big.Int
+	Reserve1 *big.Int
+}
+
+// ParseSync parses a Uniswap V2 Sync log into a Sync struct.
+// Returns nil if the log is not a valid sync event.
+func ParseSync(log *types.Log) *Sync {
+	if len(log.Topics) != 1 || log.Topics[0] != SyncSignature || len(log.Data) < 64 {
+		return nil
+	}
+
+	return &Sync{
+		Pool:     log.Address,
+		Reserve0: new(big.Int).SetBytes(log.Data[:32]),
+		Reserve1: new(big.Int).SetBytes(log.Data[32:64]),
+	}
+}
+
+// Snapshot is the reserve state of a V2 pool around a single swap.
+type Snapshot struct {
+	TxIndex        int
+	Swap           *V2Swap
+	Reserve0Before *big.Int
+	Reserve1Before *big.Int
+	Reserve0After  *big.Int
+	Reserve1After  *big.Int
+}
+
+// SpotPriceBefore returns the pool price before the swap, in token1 per token0.
+func (s *Snapshot) SpotPriceBefore() *big.Float {
+	return ratio(s.Reserve1Before, s.Reserve0Before)
+}
+
+// SpotPriceAfter returns the pool price after the swap, in token1 per token0.
+func (s *Snapshot) SpotPriceAfter() *big.Float {
+	return ratio(s.Reserve1After, s.Reserve0After)
+}
+
+// ExecutionPrice returns the average price the swap executed at, in token1 per token0.
+func (s *Snapshot) ExecutionPrice() *big.Float {
+	if s.Swap.IsToken0To1() {
+		return ratio(s.Swap.AmountOut(), s.Swap.AmountIn())
+	}
+	return ratio(s.Swap.AmountIn(), s.Swap.AmountOut())
+}
+
+// PriceImpact returns the relative move of the spot price caused by the swap,
+// |after - before| / before. Returns nil if the pool was empty.
+func (s *Snapshot) PriceImpact() *big.Float {
+	before, after := s.SpotPriceBefore(), s.SpotPriceAfter()
+	if before == nil || after == nil || before.Sign() == 0 {
+		return nil
+	}
+	impact := new(big.Float).Sub(after, before)
+	return impact.Abs(impact).Quo(impact, before)
+}
+
+// PoolState follows the reserves of V2 pools through a bundle, recording one Snapshot per swap.
+type PoolState struct {
+	reserves  map[common.Address][2]*big.Int
+	snapshots map[common.Address][]*Snapshot
+}
+
+// NewPoolState returns an empty PoolState.
+func NewPoolState() *PoolState {
+	return &PoolState{
+		reserves:  make(map[common.Address][2]*big.Int),
+		snapshots: make(map[common.Address][]*Snapshot),
+	}
+}
+
+// ObserveSync records the reserves reported by a Sync event.
+func (p *PoolState) ObserveSync(sync *Sync) {
+	p.reserves[sync.Pool] = [2]*big.Int{sync.Reserve0, sync.Reserve1}
+}
+
+// ObserveSwap records a snapshot for a swap, using the reserves of sync as the post-swap
+// state. sync must be the log immediately preceding the Swap, as the pair emits Sync right
+// before Swap; a Sync logged earlier may predate other changes to the reserves. Returns nil
+// if sync is nil or was emitted by another pool.
+func (p *PoolState) ObserveSwap(txIndex int, sync *Sync, swap *V2Swap) *Snapshot {
+	if sync == nil || sync.Pool != swap.pool {
+		return nil
+	}
+	reserves := [2]*big.Int{sync.Reserve0, sync.Reserve1}
+
+	snapshot := &Snapshot{
+		TxIndex:        txIndex,
+		Swap:           swap,
+		Reserve0After:  reserves[0],
+		Reserve1After:  reserves[1],
+		Reserve0Before: new(big.Int).Add(new(big.Int).Sub(reserves[0], swap.amount0In), swap.amount0Out),
+		Reserve1Before: new(big.Int).Add(new(big.Int).Sub(reserves[1], swap.amount1In), swap.amount1Out),
+	}
+	p.snapshots[swap.pool] = append(p.snapshots[swap.pool], snapshot)
+	return snapshot
+}
+
+// Reserves returns the latest known reserves of a pool.
+func (p *PoolState) Reserves(pool common.Address) (reserve0, reserve1 *big.Int, ok bool) {
+	reserves, ok := p.reserves[pool]
+	return reserves[0], reserves[1], ok
+}
+
+// Snapshots returns the snapshots recorded for a pool, in bundle order.
+func (p *PoolState) Snapshots(pool common.Address) []*Snapshot {
+	return p.snapshots[pool]
+}
+
+// TxSnapshots returns the snapshots recorded for a pool within one transaction.
+func (p *PoolState) TxSnapshots(pool common.Address, txIndex int) []*Snapshot {
+	var snapshots []*Snapshot
+	for _, snapshot := range p.snapshots[pool] {
+		if snapshot.TxIndex == txIndex {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots
+}
+
+func ratio(numerator, denominator *big.Int) *big.Float {
+	if denominator.Sign() == 0 {
+		return nil
+	}
+	return new(big.Float).Quo(new(big.Float).SetInt(numerator), new(big.Float).SetInt(denominator))
+}
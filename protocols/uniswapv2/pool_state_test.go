@@ -0,0 +1,50 @@
+package uniswapv2
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPoolState(t *testing.T) {
+	pool := common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+
+	sync := ParseSync(logtest.V2Sync(pool, 1100000, 909091))
+	if sync == nil || sync.Pool != pool || sync.Reserve0.Int64() != 1100000 || sync.Reserve1.Int64() != 909091 {
+		t.Fatalf("ParseSync() = %+v", sync)
+	}
+
+	// Another event with the same layout is not a Sync.
+	if other := ParseSync(logtest.Log(pool, "Mint(address,uint256,uint256)", nil, logtest.Word(1), logtest.Word(2))); other != nil {
+		t.Errorf("ParseSync() other event = %+v, want nil", other)
+	}
+
+	state := NewPoolState()
+	state.ObserveSync(sync)
+	snapshot := state.ObserveSwap(0, sync, ParseSwap(logtest.V2Swap(pool, trader, 100000, 0, 0, 90909)))
+	if snapshot == nil {
+		t.Fatal("ObserveSwap() = nil")
+	}
+	if snapshot.Reserve0Before.Int64() != 1000000 || snapshot.Reserve1Before.Int64() != 1000000 {
+		t.Errorf("ObserveSwap() reserves before = %v, %v, want 1000000, 1000000", snapshot.Reserve0Before, snapshot.Reserve1Before)
+	}
+	if price, _ := snapshot.SpotPriceBefore().Float64(); price != 1 {
+		t.Errorf("SpotPriceBefore() = %v, want 1", price)
+	}
+	if impact, _ := snapshot.PriceImpact().Float64(); impact < 0.17 || impact > 0.18 {
+		t.Errorf("PriceImpact() = %v, want ~0.174", impact)
+	}
+	if len(state.TxSnapshots(pool, 0)) != 1 || len(state.TxSnapshots(pool, 1)) != 0 {
+		t.Errorf("TxSnapshots() = %d in tx 0, %d in tx 1, want 1 and 0", len(state.TxSnapshots(pool, 0)), len(state.TxSnapshots(pool, 1)))
+	}
+
+	other := common.HexToAddress("0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16")
+	if snapshot := state.ObserveSwap(0, nil, ParseSwap(logtest.V2Swap(other, trader, 1000, 0, 0, 3))); snapshot != nil {
+		t.Errorf("ObserveSwap() without reserves = %+v, want nil", snapshot)
+	}
+	if snapshot := state.ObserveSwap(0, sync, ParseSwap(logtest.V2Swap(other, trader, 1000, 0, 0, 3))); snapshot != nil {
+		t.Errorf("ObserveSwap() with another pool's Sync = %+v, want nil", snapshot)
+	}
+}
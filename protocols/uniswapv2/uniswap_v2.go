@@ -1,4 +1,4 @@
-// Package uniswapv2 provides swap event parsing for Uniswap V2 and compatible protocols.
+// Package uniswapv2 provides swap and liquidity event parsing for Uniswap V2 and compatible protocols.
 package uniswapv2
 
 import (
@@ -0,0 +1,220 @@
+package uniswapv3
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PositionChange is a Mint or Burn of a concentrated liquidity position.
+type PositionChange struct {
+	pool      common.Address
+	owner     common.Address
+	tickLower int32
+	tickUpper int32
+	liquidity *big.Int
+	amount0   *big.Int
+	amount1   *big.Int
+	add       bool
+
+	// collected0/1 are the amounts sent by a Collect of the position after a Burn, nil if none was seen
+	collected0 *big.Int
+	collected1 *big.Int
+
+	// tokenID and holder identify the position manager NFT the change was made for, if known
+	tokenID *big.Int
+	holder  common.Address
+}
+
+// PairID returns the pool address.
+func (p *PositionChange) PairID() common.Address {
+	return p.pool
+}
+
+// Provider returns the owner of the position.
+func (p *PositionChange) Provider() common.Address {
+	return p.owner
+}
+
+// PositionID returns the pool's position key, keccak256(owner, tickLower, tickUpper). A position
+// manager holds the liquidity of all its NFTs of a range under one key, so once the NFT is
+// known the key is hashed again with its token id.
+func (p *PositionChange) PositionID() common.Hash {
+	key := positionKey(p.owner, p.tickLower, p.tickUpper)
+	if p.tokenID == nil {
+		return key
+	}
+	return crypto.Keccak256Hash(key.Bytes(), common.BigToHash(p.tokenID).Bytes())
+}
+
+// SetTokenID records the position manager NFT the change was made for, see ManagerChange.
+func (p *PositionChange) SetTokenID(tokenID *big.Int) {
+	p.tokenID = tokenID
+}
+
+// TokenID returns the position manager NFT the change was made for, nil if unknown.
+func (p *PositionChange) TokenID() *big.Int {
+	return p.tokenID
+}
+
+// SetHolder records the holder of the position manager NFT.
+func (p *PositionChange) SetHolder(holder common.Address) {
+	p.holder = holder
+}
+
+// Holder returns the holder of the position manager NFT, the zero address if unknown.
+func (p *PositionChange) Holder() common.Address {
+	return p.holder
+}
+
+// IsAdd returns true for a Mint and false for a Burn.
+func (p *PositionChange) IsAdd() bool {
+	return p.add
+}
+
+// Amount0 returns the token0 amount deposited or withdrawn.
+func (p *PositionChange) Amount0() *big.Int {
+	return p.amount0
+}
+
+// Amount1 returns the token1 amount deposited or withdrawn.
+func (p *PositionChange) Amount1() *big.Int {
+	return p.amount1
+}
+
+// Liquidity returns the liquidity added or removed.
+func (p *PositionChange) Liquidity() *big.Int {
+	return p.liquidity
+}
+
+// Ticks returns the tick range of the position.
+func (p *PositionChange) Ticks() (lower, upper int32) {
+	return p.tickLower, p.tickUpper
+}
+
+// Fees returns the fees collected with a Burn, i.e. the collected amounts minus the
+// withdrawn principal. Both are nil unless a Collect of the position followed the Burn.
+func (p *PositionChange) Fees() (token0, token1 *big.Int) {
+	if p.add || p.collected0 == nil {
+		return nil, nil
+	}
+	return new(big.Int).Sub(p.collected0, p.amount0), new(big.Int).Sub(p.collected1, p.amount1)
+}
+
+// AddCollect records a Collect of the position. It reports false if the Collect is for another position.
+func (p *PositionChange) AddCollect(collect *Collect) bool {
+	if p.add || collect.pool != p.pool || collect.PositionID() != positionKey(p.owner, p.tickLower, p.tickUpper) {
+		return false
+	}
+	if p.collected0 == nil {
+		p.collected0, p.collected1 = new(big.Int), new(big.Int)
+	}
+	p.collected0.Add(p.collected0, collect.amount0)
+	p.collected1.Add(p.collected1, collect.amount1)
+	return true
+}
+
+// Collect is a withdrawal of the tokens owed to a position: burned principal and fees.
+type Collect struct {
+	pool      common.Address
+	owner     common.Address
+	recipient common.Address
+	tickLower int32
+	tickUpper int32
+	amount0   *big.Int
+	amount1   *big.Int
+}
+
+// PositionID returns the pool's position key, keccak256(owner, tickLower, tickUpper).
+func (c *Collect) PositionID() common.Hash {
+	return positionKey(c.owner, c.tickLower, c.tickUpper)
+}
+
+// Recipient returns the address the tokens were sent to.
+func (c *Collect) Recipient() common.Address {
+	return c.recipient
+}
+
+// Amounts returns the token0 and token1 amounts collected.
+func (c *Collect) Amounts() (token0, token1 *big.Int) {
+	return c.amount0, c.amount1
+}
+
+// ParseMint parses a V3 Mint log into a PositionChange.
+// Returns nil if the log is not a valid mint event.
+func ParseMint(log *types.Log) *PositionChange {
+	// Topics: signature, owner, tickLower, tickUpper
+	// Data layout: sender, amount, amount0, amount1
+	if len(log.Topics) != 4 || len(log.Data) < 128 {
+		return nil
+	}
+
+	return &PositionChange{
+		pool:      log.Address,
+		owner:     common.BytesToAddress(log.Topics[1].Bytes()),
+		tickLower: decodeTick(log.Topics[2]),
+		tickUpper: decodeTick(log.Topics[3]),
+		liquidity: new(big.Int).SetBytes(log.Data[32:64]),
+		amount0:   new(big.Int).SetBytes(log.Data[64:96]),
+		amount1:   new(big.Int).SetBytes(log.Data[96:128]),
+		add:       true,
+	}
+}
+
+// ParseBurn parses a V3 Burn log into a PositionChange.
+// Returns nil if the log is not a valid burn event.
+func ParseBurn(log *types.Log) *PositionChange {
+	// Topics: signature, owner, tickLower, tickUpper
+	// Data layout: amount, amount0, amount1
+	if len(log.Topics) != 4 || len(log.Data) < 96 {
+		return nil
+	}
+
+	return &PositionChange{
+		pool:      log.Address,
+		owner:     common.BytesToAddress(log.Topics[1].Bytes()),
+		tickLower: decodeTick(log.Topics[2]),
+		tickUpper: decodeTick(log.Topics[3]),
+		liquidity: new(big.Int).SetBytes(log.Data[:32]),
+		amount0:   new(big.Int).SetBytes(log.Data[32:64]),
+		amount1:   new(big.Int).SetBytes(log.Data[64:96]),
+	}
+}
+
+// ParseCollect parses a V3 Collect log.
+// Returns nil if the log is not a valid collect event.
+func ParseCollect(log *types.Log) *Collect {
+	// Topics: signature, owner, tickLower, tickUpper
+	// Data layout: recipient, amount0, amount1
+	if len(log.Topics) != 4 || len(log.Data) < 96 {
+		return nil
+	}
+
+	return &Collect{
+		pool:      log.Address,
+		owner:     common.BytesToAddress(log.Topics[1].Bytes()),
+		recipient: common.BytesToAddress(log.Data[:32]),
+		tickLower: decodeTick(log.Topics[2]),
+		tickUpper: decodeTick(log.Topics[3]),
+		amount0:   new(big.Int).SetBytes(log.Data[32:64]),
+		amount1:   new(big.Int).SetBytes(log.Data[64:96]),
+	}
+}
+
+// decodeTick decodes an int24 tick from an indexed topic.
+func decodeTick(topic common.Hash) int32 {
+	return int32(tools.DecodeSignedInt256(topic.Bytes()).Int64())
+}
+
+// positionKey computes the position key the pool stores positions under.
+func positionKey(owner common.Address, tickLower, tickUpper int32) common.Hash {
+	return crypto.Keccak256Hash(owner.Bytes(), int24Bytes(tickLower), int24Bytes(tickUpper))
+}
+
+// int24Bytes returns the abi.encodePacked form of an int24.
+func int24Bytes(tick int32) []byte {
+	return []byte{byte(tick >> 16), byte(tick >> 8), byte(tick)}
+}
@@ -0,0 +1,53 @@
+package uniswapv3
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParsePositionChange(t *testing.T) {
+	pool := common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000b0771")
+	position := []common.Hash{logtest.AddressTopic(owner), logtest.IntTopic(-10), logtest.IntTopic(10)}
+
+	mint := ParseMint(logtest.Log(pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.AddressWord(owner), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)))
+	if mint == nil || !mint.IsAdd() || mint.Provider() != owner || mint.Liquidity().Int64() != 5000 || mint.Amount0().Int64() != 1000 {
+		t.Fatalf("ParseMint() = %+v", mint)
+	}
+	if lower, upper := mint.Ticks(); lower != -10 || upper != 10 {
+		t.Errorf("Ticks() = %d, %d, want -10, 10", lower, upper)
+	}
+	// keccak256(abi.encodePacked(owner, int24(-10), int24(10)))
+	want := crypto.Keccak256Hash(owner.Bytes(), []byte{0xff, 0xff, 0xf6}, []byte{0x00, 0x00, 0x0a})
+	if mint.PositionID() != want {
+		t.Errorf("PositionID() = %s, want %s", mint.PositionID().Hex(), want.Hex())
+	}
+
+	burn := ParseBurn(logtest.Log(pool, "Burn(address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.Word(5000), logtest.Word(1290), logtest.Word(1710)))
+	if burn == nil || burn.IsAdd() || burn.PositionID() != mint.PositionID() {
+		t.Fatalf("ParseBurn() = %+v", burn)
+	}
+	if fee0, fee1 := burn.Fees(); fee0 != nil || fee1 != nil {
+		t.Errorf("Fees() before Collect = %v, %v, want nil", fee0, fee1)
+	}
+
+	collect := ParseCollect(logtest.Log(pool, "Collect(address,address,int24,int24,uint128,uint128)", position,
+		logtest.AddressWord(owner), logtest.Word(1291), logtest.Word(1712)))
+	if collect == nil || collect.Recipient() != owner {
+		t.Fatalf("ParseCollect() = %+v", collect)
+	}
+	if !burn.AddCollect(collect) {
+		t.Fatal("AddCollect() = false, want the Collect of the burned position")
+	}
+	if fee0, fee1 := burn.Fees(); fee0.Int64() != 1 || fee1.Int64() != 2 {
+		t.Errorf("Fees() = %v, %v, want 1, 2", fee0, fee1)
+	}
+	if mint.AddCollect(collect) {
+		t.Error("AddCollect() on a Mint = true, want false")
+	}
+}
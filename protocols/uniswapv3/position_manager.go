@@ -0,0 +1,50 @@
+package uniswapv3
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// NonfungiblePositionManager IncreaseLiquidity and DecreaseLiquidity event signatures
+	increaseLiquiditySignature = common.HexToHash("0x3067048beee31b25b2f1681f88dac838c8bba36af25bfb2b7cf7473a5847e35f")
+	decreaseLiquiditySignature = common.HexToHash("0x26f6a048ee9138f2c0ce266f322cb99228e8d619ae2bff30c67f8dcf9d2377b4")
+)
+
+// ManagerChange is an IncreaseLiquidity or DecreaseLiquidity event of a NonfungiblePositionManager.
+// The manager owns the pool positions of all its NFTs and emits the event right after the
+// pool's Mint or Burn, naming the NFT the liquidity belongs to.
+type ManagerChange struct {
+	Manager   common.Address
+	TokenID   *big.Int
+	Liquidity *big.Int
+	Increase  bool
+}
+
+// ParseManagerChange parses a position manager IncreaseLiquidity or DecreaseLiquidity log.
+// Returns nil if the log is not a valid event.
+func ParseManagerChange(log *types.Log) *ManagerChange {
+	// Topics: signature, tokenId
+	// Data layout: liquidity, amount0, amount1
+	if len(log.Topics) != 2 || len(log.Data) < 96 {
+		return nil
+	}
+
+	var increase bool
+	switch log.Topics[0] {
+	case increaseLiquiditySignature:
+		increase = true
+	case decreaseLiquiditySignature:
+	default:
+		return nil
+	}
+
+	return &ManagerChange{
+		Manager:   log.Address,
+		TokenID:   new(big.Int).SetBytes(log.Topics[1].Bytes()),
+		Liquidity: new(big.Int).SetBytes(log.Data[:32]),
+		Increase:  increase,
+	}
+}
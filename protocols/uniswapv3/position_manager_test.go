@@ -0,0 +1,50 @@
+package uniswapv3
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseManagerChange(t *testing.T) {
+	manager := common.HexToAddress("0x46A15B0b27311cedF172AB29E4f4766fbE7F4364")
+	pool := common.HexToAddress("0x172fcD41E0913e95784454622d1c3724f546f849")
+	// IncreaseLiquidity(tokenId indexed, liquidity, amount0, amount1)
+	increase := ParseManagerChange(logtest.Log(manager, "IncreaseLiquidity(uint256,uint128,uint256,uint256)",
+		[]common.Hash{logtest.IntTopic(42)}, logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)))
+	if increase == nil || !increase.Increase || increase.Manager != manager || increase.TokenID.Int64() != 42 || increase.Liquidity.Int64() != 5000 {
+		t.Fatalf("ParseManagerChange() = %+v", increase)
+	}
+	decrease := ParseManagerChange(logtest.Log(manager, "DecreaseLiquidity(uint256,uint128,uint256,uint256)",
+		[]common.Hash{logtest.IntTopic(42)}, logtest.Word(5000), logtest.Word(1290), logtest.Word(1710)))
+	if decrease == nil || decrease.Increase {
+		t.Fatalf("ParseManagerChange() decrease = %+v", decrease)
+	}
+
+	// The manager owns the pool positions of all its NFTs: the token id tells them apart.
+	position := []common.Hash{logtest.AddressTopic(manager), logtest.IntTopic(-10), logtest.IntTopic(10)}
+	mint := func() *PositionChange {
+		return ParseMint(logtest.Log(pool, "Mint(address,address,int24,int24,uint128,uint256,uint256)", position,
+			logtest.AddressWord(manager), logtest.Word(5000), logtest.Word(1000), logtest.Word(2000)))
+	}
+	first, second := mint(), mint()
+	if first.PositionID() != second.PositionID() {
+		t.Fatal("PositionID() differs for one pool position")
+	}
+	first.SetTokenID(big.NewInt(42))
+	second.SetTokenID(big.NewInt(43))
+	if first.PositionID() == second.PositionID() {
+		t.Error("PositionID() ignores the token id")
+	}
+
+	burn := ParseBurn(logtest.Log(pool, "Burn(address,int24,int24,uint128,uint256,uint256)", position,
+		logtest.Word(5000), logtest.Word(1290), logtest.Word(1710)))
+	burn.SetTokenID(big.NewInt(42))
+	collect := ParseCollect(logtest.Log(pool, "Collect(address,address,int24,int24,uint128,uint128)", position,
+		logtest.AddressWord(manager), logtest.Word(1291), logtest.Word(1710)))
+	if !burn.AddCollect(collect) {
+		t.Error("AddCollect() = false, want the Collect of the pool position")
+	}
+}
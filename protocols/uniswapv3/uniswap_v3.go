@@ -1,4 +1,4 @@
-// Package uniswapv3 provides swap event parsing for Uniswap V3 and compatible protocols.
+// Package uniswapv3 provides swap and liquidity event parsing for Uniswap V3 and compatible protocols.
 package uniswapv3
 
 import (
@@ -9,12 +9,22 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// pancakeV3SwapSignature is the PancakeSwap V3 swap event, which appends protocolFeesToken0/1.
+var pancakeV3SwapSignature = common.HexToHash("0x19b47279256b2a23a1665c810c8d55a1758940ee09377d4f8d26497a3577dc83")
+
 // V3Swap implements SwapEvent for Uniswap V3-style pools.
 type V3Swap struct {
-	pool       common.Address
-	amount0    *big.Int
-	amount1    *big.Int
-	zeroForOne bool
+	pool         common.Address
+	amount0      *big.Int
+	amount1      *big.Int
+	zeroForOne   bool
+	sqrtPriceX96 *big.Int
+	liquidity    *big.Int
+	tick         int32
+
+	// protocolFees0/1 are only reported by PancakeSwap V3 pools, nil otherwise
+	protocolFees0 *big.Int
+	protocolFees1 *big.Int
 }
 
 // PairID returns the pool address.
@@ -43,9 +53,31 @@ func (s *V3Swap) AmountOut() *big.Int {
 	return new(big.Int).Abs(s.amount0)
 }
 
+// SqrtPriceX96 returns the sqrt price of the pool after the swap, as a Q64.96.
+func (s *V3Swap) SqrtPriceX96() *big.Int {
+	return s.sqrtPriceX96
+}
+
+// Liquidity returns the in-range liquidity of the pool after the swap.
+func (s *V3Swap) Liquidity() *big.Int {
+	return s.liquidity
+}
+
+// Tick returns the tick of the pool after the swap.
+func (s *V3Swap) Tick() int32 {
+	return s.tick
+}
+
+// ProtocolFees returns the protocol fees charged in token0 and token1.
+// Both are nil unless the swap was emitted by a PancakeSwap V3 pool.
+func (s *V3Swap) ProtocolFees() (token0, token1 *big.Int) {
+	return s.protocolFees0, s.protocolFees1
+}
+
 // ParseSwap parses a Uniswap V3 swap log into a V3Swap struct.
 // Returns nil if the log is not a valid swap event.
 func ParseSwap(log *types.Log) *V3Swap {
+	// Data layout: amount0, amount1, sqrtPriceX96, liquidity, tick[, protocolFeesToken0, protocolFeesToken1]
 	if len(log.Data) < 160 {
 		return nil
 	}
@@ -53,10 +85,18 @@ func ParseSwap(log *types.Log) *V3Swap {
 	amount0 := tools.DecodeSignedInt256(log.Data[:32])
 	amount1 := tools.DecodeSignedInt256(log.Data[32:64])
 
-	return &V3Swap{
-		pool:       log.Address,
-		amount0:    amount0,
-		amount1:    amount1,
-		zeroForOne: amount0.Cmp(amount1) > 0,
+	swap := &V3Swap{
+		pool:         log.Address,
+		amount0:      amount0,
+		amount1:      amount1,
+		zeroForOne:   amount0.Cmp(amount1) > 0,
+		sqrtPriceX96: new(big.Int).SetBytes(log.Data[64:96]),
+		liquidity:    new(big.Int).SetBytes(log.Data[96:128]),
+		tick:         int32(tools.DecodeSignedInt256(log.Data[128:160]).Int64()),
+	}
+	if len(log.Topics) > 0 && log.Topics[0] == pancakeV3SwapSignature && len(log.Data) >= 224 {
+		swap.protocolFees0 = new(big.Int).SetBytes(log.Data[160:192])
+		swap.protocolFees1 = new(big.Int).SetBytes(log.Data[192:224])
 	}
+	return swap
 }
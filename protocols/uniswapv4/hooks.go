@@ -0,0 +1,89 @@
+package uniswapv4
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HookSwap represents the swap accounting reported by a hook that took over
+// (part of) a swap, either through the community-standard HookSwap event or a
+// hook-specific event decoded by a HookAdapter.
+// Amount0 and Amount1 are balance deltas of the swapper like the amounts of the PoolManager
+// Swap event: negative for the currency paid in, positive for the currency taken out.
+type HookSwap struct {
+	PoolID   common.Hash
+	Hook     common.Address
+	Amount0  *big.Int
+	Amount1  *big.Int
+	HookFee0 *big.Int
+	HookFee1 *big.Int
+}
+
+// HookAdapter decodes the swap accounting of a custom-curve hook from its own events.
+// ParseHookSwap returns nil for logs it does not recognize, and signs the amounts it
+// returns from the swapper's side, see HookSwap.
+type HookAdapter interface {
+	ParseHookSwap(log *types.Log) *HookSwap
+}
+
+// HookAdapters maps hook contracts to the adapters decoding their events.
+type HookAdapters map[common.Address]HookAdapter
+
+// ParseLog decodes a log through the adapter of its emitter.
+// Returns nil if the emitter has no adapter or the adapter does not recognize the log.
+func (a HookAdapters) ParseLog(log *types.Log) *HookSwap {
+	adapter := a[log.Address]
+	if adapter == nil {
+		return nil
+	}
+	return adapter.ParseHookSwap(log)
+}
+
+// ParseHookSwap parses a standard HookSwap log into a HookSwap struct.
+// Returns nil if the log is not a valid HookSwap event.
+func ParseHookSwap(log *types.Log) *HookSwap {
+	// Data layout: amount0, amount1, hookLPfeeAmount0, hookLPfeeAmount1
+	if len(log.Topics) != 3 || len(log.Data) < 128 {
+		return nil
+	}
+
+	return &HookSwap{
+		PoolID:   log.Topics[1],
+		Hook:     log.Address,
+		Amount0:  tools.DecodeSignedInt256(log.Data[:32]),
+		Amount1:  tools.DecodeSignedInt256(log.Data[32:64]),
+		HookFee0: new(big.Int).SetBytes(log.Data[64:96]),
+		HookFee1: new(big.Int).SetBytes(log.Data[96:128]),
+	}
+}
+
+// CombineHookSwaps merges the PoolManager swaps of one pool with the hook-reported deltas
+// for it, so that custom-curve swaps read as a single swap with the right direction. Both
+// are swapper balance deltas, so they are summed.
+// swaps may be empty when the hook settled the swap without a PoolManager Swap event.
+func CombineHookSwaps(swaps []*V4Swap, hookSwaps []*HookSwap) *V4Swap {
+	combined := &V4Swap{
+		amount0:   new(big.Int),
+		amount1:   new(big.Int),
+		hookSwaps: hookSwaps,
+	}
+	if len(hookSwaps) > 0 {
+		combined.poolID = hookSwaps[0].PoolID
+	}
+
+	for _, swap := range swaps {
+		combined.poolID, combined.manager = swap.poolID, swap.manager
+		combined.sqrtPriceX96, combined.liquidity = swap.sqrtPriceX96, swap.liquidity
+		combined.tick, combined.fee = swap.tick, swap.fee
+		combined.amount0.Add(combined.amount0, swap.amount0)
+		combined.amount1.Add(combined.amount1, swap.amount1)
+	}
+	for _, hookSwap := range hookSwaps {
+		combined.amount0.Add(combined.amount0, hookSwap.Amount0)
+		combined.amount1.Add(combined.amount1, hookSwap.Amount1)
+	}
+	return combined
+}
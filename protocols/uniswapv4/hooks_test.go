@@ -0,0 +1,52 @@
+package uniswapv4
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCombineHookSwaps(t *testing.T) {
+	poolID := common.HexToHash("0xc012e144f83cd4c616704b5391205ad0dc19719ca9f89b739a67a9b1d5316f17")
+	hook := common.HexToAddress("0x1e9c64cad39DDD36fB808E004067Cffc710EB71D")
+	sender := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+
+	hookSwap := ParseHookSwap(logtest.Log(hook, "HookSwap(bytes32,address,int128,int128,uint128,uint128)",
+		[]common.Hash{poolID, logtest.AddressTopic(sender)}, logtest.Word(-1000), logtest.Word(990), logtest.Word(3), logtest.Word(0)))
+	if hookSwap == nil || hookSwap.PoolID != poolID || hookSwap.Hook != hook || hookSwap.Amount1.Int64() != 990 || hookSwap.HookFee0.Int64() != 3 {
+		t.Fatalf("ParseHookSwap() = %+v", hookSwap)
+	}
+
+	// The hook settled the swap itself, the PoolManager swap carries no delta.
+	managerSwap := ParseSwap(logtest.Log(common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
+		"Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24)",
+		[]common.Hash{poolID, logtest.AddressTopic(sender)},
+		logtest.Word(0), logtest.Word(0), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(-5), logtest.Word(3000)))
+	if managerSwap == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+
+	combined := CombineHookSwaps([]*V4Swap{managerSwap}, []*HookSwap{hookSwap})
+	if combined.PoolID() != poolID || !combined.IsToken0To1() || combined.AmountIn().Int64() != 1000 || combined.AmountOut().Int64() != 990 {
+		t.Errorf("CombineHookSwaps() = 0 to 1 %v, %v -> %v, want token0 -> token1, 1000 -> 990", combined.IsToken0To1(), combined.AmountIn(), combined.AmountOut())
+	}
+	if combined.Tick() != -5 || len(combined.HookSwaps()) != 1 {
+		t.Errorf("CombineHookSwaps() tick = %d, hook swaps = %d, want -5 and 1", combined.Tick(), len(combined.HookSwaps()))
+	}
+
+	// The PoolManager filled part of the swap and the hook the rest, both from the swapper's side.
+	partial := ParseSwap(logtest.Log(common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
+		"Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24)",
+		[]common.Hash{poolID, logtest.AddressTopic(sender)},
+		logtest.Word(-500), logtest.Word(495), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(-5), logtest.Word(3000)))
+	combined = CombineHookSwaps([]*V4Swap{partial}, []*HookSwap{hookSwap})
+	if !combined.IsToken0To1() || combined.AmountIn().Int64() != 1500 || combined.AmountOut().Int64() != 1485 {
+		t.Errorf("CombineHookSwaps() partial fill = 0 to 1 %v, %v -> %v, want token0 -> token1, 1500 -> 1485", combined.IsToken0To1(), combined.AmountIn(), combined.AmountOut())
+	}
+
+	hookOnly := CombineHookSwaps(nil, []*HookSwap{hookSwap})
+	if hookOnly.PoolID() != poolID || hookOnly.AmountIn().Int64() != 1000 {
+		t.Errorf("CombineHookSwaps() without PoolManager swap = %+v", hookOnly)
+	}
+}
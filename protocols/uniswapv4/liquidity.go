@@ -0,0 +1,114 @@
+package uniswapv4
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ModifyLiquidity is a PoolManager liquidity change of a position.
+// The PoolManager does not report the token amounts, so Amount0 and Amount1 are nil.
+type ModifyLiquidity struct {
+	poolID         [32]byte
+	sender         common.Address
+	tickLower      int32
+	tickUpper      int32
+	liquidityDelta *big.Int
+	salt           common.Hash
+	holder         common.Address
+}
+
+// PairID returns a pseudo-address derived from the first 20 bytes of poolID, matching V4Swap.PairID.
+func (m *ModifyLiquidity) PairID() common.Address {
+	return common.BytesToAddress(m.poolID[:20])
+}
+
+// PoolID returns the full 32-byte PoolId of the pool.
+func (m *ModifyLiquidity) PoolID() common.Hash {
+	return m.poolID
+}
+
+// Provider returns the address that modified the position, usually a position manager.
+func (m *ModifyLiquidity) Provider() common.Address {
+	return m.sender
+}
+
+// PositionID returns the PoolManager's position key, keccak256(sender, tickLower, tickUpper, salt).
+func (m *ModifyLiquidity) PositionID() common.Hash {
+	return crypto.Keccak256Hash(
+		m.sender.Bytes(),
+		[]byte{byte(m.tickLower >> 16), byte(m.tickLower >> 8), byte(m.tickLower)},
+		[]byte{byte(m.tickUpper >> 16), byte(m.tickUpper >> 8), byte(m.tickUpper)},
+		m.salt.Bytes(),
+	)
+}
+
+// TokenID returns the salt as a number. Position managers use the NFT token id as salt.
+func (m *ModifyLiquidity) TokenID() *big.Int {
+	return new(big.Int).SetBytes(m.salt.Bytes())
+}
+
+// SetHolder records the holder of the position manager NFT.
+func (m *ModifyLiquidity) SetHolder(holder common.Address) {
+	m.holder = holder
+}
+
+// Holder returns the holder of the position manager NFT, the zero address if unknown.
+func (m *ModifyLiquidity) Holder() common.Address {
+	return m.holder
+}
+
+// IsAdd returns true if liquidity was added to the position.
+func (m *ModifyLiquidity) IsAdd() bool {
+	return m.liquidityDelta.Sign() > 0
+}
+
+// Amount0 returns nil, the token0 amount is not part of the event.
+func (m *ModifyLiquidity) Amount0() *big.Int {
+	return nil
+}
+
+// Amount1 returns nil, the token1 amount is not part of the event.
+func (m *ModifyLiquidity) Amount1() *big.Int {
+	return nil
+}
+
+// LiquidityDelta returns the signed liquidity change.
+func (m *ModifyLiquidity) LiquidityDelta() *big.Int {
+	return m.liquidityDelta
+}
+
+// Ticks returns the tick range of the position.
+func (m *ModifyLiquidity) Ticks() (lower, upper int32) {
+	return m.tickLower, m.tickUpper
+}
+
+// ParseModifyLiquidity parses a V4 ModifyLiquidity log.
+// Returns nil if the log is not a valid event or does not change liquidity (a fee collection).
+func ParseModifyLiquidity(log *types.Log) *ModifyLiquidity {
+	// Topics: signature, id, sender
+	// Data layout: tickLower, tickUpper, liquidityDelta, salt
+	if len(log.Topics) != 3 || len(log.Data) < 128 {
+		return nil
+	}
+
+	liquidityDelta := tools.DecodeSignedInt256(log.Data[64:96])
+	if liquidityDelta.Sign() == 0 {
+		return nil
+	}
+
+	var poolID [32]byte
+	copy(poolID[:], log.Topics[1].Bytes())
+
+	return &ModifyLiquidity{
+		poolID:         poolID,
+		sender:         common.BytesToAddress(log.Topics[2].Bytes()),
+		tickLower:      int32(tools.DecodeSignedInt256(log.Data[:32]).Int64()),
+		tickUpper:      int32(tools.DecodeSignedInt256(log.Data[32:64]).Int64()),
+		liquidityDelta: liquidityDelta,
+		salt:           common.BytesToHash(log.Data[96:128]),
+	}
+}
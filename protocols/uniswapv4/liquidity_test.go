@@ -0,0 +1,39 @@
+package uniswapv4
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseModifyLiquidity(t *testing.T) {
+	manager := common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF")
+	poolID := common.HexToHash("0xc012e144f83cd4c616704b5391205ad0dc19719ca9f89b739a67a9b1d5316f17")
+	positionManager := common.HexToAddress("0x7A4a5c919aE2541AeD11041A1AEeE68f1287f95b")
+	modify := func(liquidityDelta int64, salt common.Hash) *ModifyLiquidity {
+		return ParseModifyLiquidity(logtest.Log(manager, "ModifyLiquidity(bytes32,address,int24,int24,int256,bytes32)",
+			[]common.Hash{poolID, logtest.AddressTopic(positionManager)},
+			logtest.Word(-60), logtest.Word(60), logtest.Word(liquidityDelta), salt.Bytes()))
+	}
+
+	add := modify(5000, common.HexToHash("0x01"))
+	if add == nil || !add.IsAdd() || add.PoolID() != poolID || add.Provider() != positionManager || add.Amount0() != nil {
+		t.Fatalf("ParseModifyLiquidity() = %+v", add)
+	}
+	if lower, upper := add.Ticks(); lower != -60 || upper != 60 {
+		t.Errorf("Ticks() = %d, %d, want -60, 60", lower, upper)
+	}
+
+	remove := modify(-5000, common.HexToHash("0x01"))
+	if remove == nil || remove.IsAdd() || remove.PositionID() != add.PositionID() {
+		t.Errorf("ParseModifyLiquidity() removal = %+v, want the same position", remove)
+	}
+	// Positions of the same owner and range are told apart by their salt, e.g. the NFT token id.
+	if other := modify(5000, common.HexToHash("0x02")); other.PositionID() == add.PositionID() {
+		t.Error("PositionID() ignores the salt")
+	}
+	if collect := modify(0, common.HexToHash("0x01")); collect != nil {
+		t.Errorf("ParseModifyLiquidity() fee collection = %+v, want nil", collect)
+	}
+}
@@ -0,0 +1,186 @@
+package uniswapv4
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PoolKey holds the immutable configuration of a V4 pool, as passed to PoolManager.initialize.
+type PoolKey struct {
+	Currency0   common.Address `json:"currency0"`
+	Currency1   common.Address `json:"currency1"`
+	Fee         uint32         `json:"fee"`
+	TickSpacing int32          `json:"tickSpacing"`
+	Hooks       common.Address `json:"hooks"`
+}
+
+// ID returns the PoolId of the pool, keccak256(abi.encode(key)).
+func (k PoolKey) ID() common.Hash {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(k.Currency0.Bytes(), 32),
+		common.LeftPadBytes(k.Currency1.Bytes(), 32),
+		common.LeftPadBytes(new(big.Int).SetUint64(uint64(k.Fee)).Bytes(), 32),
+		math.U256Bytes(big.NewInt(int64(k.TickSpacing))),
+		common.LeftPadBytes(k.Hooks.Bytes(), 32),
+	)
+}
+
+// Tokens returns the pool currencies with the native currency reported as the given
+// wrapped native token, so V4 pools key the same token pair as V2/V3 pools.
+func (k PoolKey) Tokens(wrappedNative common.Address) (token0, token1 common.Address) {
+	return tools.NormalizeToken(k.Currency0, wrappedNative), tools.NormalizeToken(k.Currency1, wrappedNative)
+}
+
+// Initialize represents a PoolManager Initialize event.
+type Initialize struct {
+	PoolID       common.Hash
+	Key          PoolKey
+	SqrtPriceX96 *big.Int
+	Tick         int32
+}
+
+// DefaultPoolManagers are the Uniswap V4 PoolManager deployments on BSC.
+var DefaultPoolManagers = []common.Address{
+	common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
+}
+
+// maxPools bounds the number of pools a PoolRegistry holds.
+const maxPools = 1 << 18
+
+// PoolRegistry maps V4 PoolIds to their PoolKey. It is safe for concurrent use.
+type PoolRegistry struct {
+	managers map[common.Address]bool
+
+	mu    sync.RWMutex
+	pools map[common.Hash]PoolKey
+}
+
+// NewPoolRegistry returns an empty PoolRegistry learning pools from the Initialize events
+// of the given PoolManager contracts, usually DefaultPoolManagers.
+func NewPoolRegistry(poolManagers ...common.Address) *PoolRegistry {
+	registry := &PoolRegistry{
+		managers: make(map[common.Address]bool),
+		pools:    make(map[common.Hash]PoolKey),
+	}
+	for _, manager := range poolManagers {
+		registry.managers[manager] = true
+	}
+	return registry
+}
+
+// Register records the PoolKey for a PoolId. It reports false, leaving the registry
+// unchanged, if the key does not hash to the PoolId, the PoolId is already known or the
+// registry is full. Since a PoolId commits to its key, a known entry never needs replacing.
+func (r *PoolRegistry) Register(id common.Hash, key PoolKey) bool {
+	if key.ID() != id {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pools[id]; ok || len(r.pools) >= maxPools {
+		return false
+	}
+	r.pools[id] = key
+	return true
+}
+
+// Lookup returns the PoolKey for a PoolId, if known.
+func (r *PoolRegistry) Lookup(id common.Hash) (PoolKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.pools[id]
+	return key, ok
+}
+
+// Observe registers the pool if log is a valid Initialize event emitted by one of the
+// registry's PoolManagers. Returns true if the pool was registered.
+func (r *PoolRegistry) Observe(log *types.Log) bool {
+	if !r.managers[log.Address] {
+		return false
+	}
+	init := ParseInitialize(log)
+	if init == nil {
+		return false
+	}
+	return r.Register(init.PoolID, init.Key)
+}
+
+// LoadJSON seeds the registry from a JSON object mapping PoolId to PoolKey, e.g.
+//
+//	{"0x21c6...ca27": {"currency0": "0x...", "currency1": "0x...", "fee": 500, "tickSpacing": 10, "hooks": "0x..."}}
+//
+// All entries are validated before any is added: an entry whose key does not hash to its
+// PoolId is an error and leaves the registry unchanged. Entries are then added as by
+// Register; entries dropped because the registry is full are reported as an error.
+func (r *PoolRegistry) LoadJSON(reader io.Reader) error {
+	var pools map[common.Hash]PoolKey
+	if err := json.NewDecoder(reader).Decode(&pools); err != nil {
+		return err
+	}
+
+	ids := make([]common.Hash, 0, len(pools))
+	for id := range pools {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	for _, id := range ids {
+		if pools[id].ID() != id {
+			return fmt.Errorf("pool %s: key does not match the PoolId", id.Hex())
+		}
+	}
+
+	dropped := 0
+	for _, id := range ids {
+		if !r.Register(id, pools[id]) {
+			if _, known := r.Lookup(id); !known {
+				dropped++
+			}
+		}
+	}
+	if dropped > 0 {
+		return fmt.Errorf("%d of %d pools not registered: registry full", dropped, len(pools))
+	}
+	return nil
+}
+
+// WriteJSON dumps the registry in the format accepted by LoadJSON.
+func (r *PoolRegistry) WriteJSON(writer io.Writer) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return json.NewEncoder(writer).Encode(r.pools)
+}
+
+// ParseInitialize parses a PoolManager Initialize log into an Initialize struct.
+// Returns nil if the log is not a valid initialize event.
+func ParseInitialize(log *types.Log) *Initialize {
+	// Topics: signature, id, currency0, currency1
+	// Data layout: fee, tickSpacing, hooks, sqrtPriceX96, tick
+	if len(log.Topics) != 4 || len(log.Data) < 160 {
+		return nil
+	}
+
+	return &Initialize{
+		PoolID: log.Topics[1],
+		Key: PoolKey{
+			Currency0:   common.BytesToAddress(log.Topics[2].Bytes()),
+			Currency1:   common.BytesToAddress(log.Topics[3].Bytes()),
+			Fee:         uint32(new(big.Int).SetBytes(log.Data[:32]).Uint64()),
+			TickSpacing: int32(tools.DecodeSignedInt256(log.Data[32:64]).Int64()),
+			Hooks:       common.BytesToAddress(log.Data[64:96]),
+		},
+		SqrtPriceX96: new(big.Int).SetBytes(log.Data[96:128]),
+		Tick:         int32(tools.DecodeSignedInt256(log.Data[128:160]).Int64()),
+	}
+}
@@ -0,0 +1,97 @@
+package uniswapv4
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPoolKeyID(t *testing.T) {
+	// Ethereum mainnet ETH/USDC 0.05% pool.
+	key := PoolKey{Currency1: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Fee: 500, TickSpacing: 10}
+	want := common.HexToHash("0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27")
+	if got := key.ID(); got != want {
+		t.Errorf("ID() = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func initializeLog(manager common.Address, id common.Hash, key PoolKey) *types.Log {
+	return logtest.Log(manager, "Initialize(bytes32,address,address,uint24,int24,address,uint160,int24)",
+		[]common.Hash{id, logtest.AddressTopic(key.Currency0), logtest.AddressTopic(key.Currency1)},
+		logtest.Word(int64(key.Fee)), logtest.Word(int64(key.TickSpacing)), logtest.AddressWord(key.Hooks),
+		logtest.Word(1<<62), logtest.Word(-41518))
+}
+
+func TestPoolRegistry(t *testing.T) {
+	manager := DefaultPoolManagers[0]
+	registry := NewPoolRegistry(manager)
+
+	key := PoolKey{Currency1: common.HexToAddress("0x55d398326f99059ff775485246999027b3197955"), Fee: 3000, TickSpacing: 60}
+	if !registry.Observe(initializeLog(manager, key.ID(), key)) {
+		t.Fatal("Observe() did not recognize the Initialize event")
+	}
+
+	got, ok := registry.Lookup(key.ID())
+	if !ok {
+		t.Fatal("Lookup() missing pool after Observe()")
+	}
+	if got != key {
+		t.Errorf("Lookup() = %+v, want %+v", got, key)
+	}
+
+	var dump bytes.Buffer
+	if err := registry.WriteJSON(&dump); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	seeded := NewPoolRegistry()
+	if err := seeded.LoadJSON(strings.NewReader(dump.String())); err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if got, _ := seeded.Lookup(key.ID()); got != key {
+		t.Errorf("LoadJSON() round trip = %+v, want %+v", got, key)
+	}
+}
+
+func TestPoolRegistryRejects(t *testing.T) {
+	manager := DefaultPoolManagers[0]
+	registry := NewPoolRegistry(manager)
+	key := PoolKey{Currency1: common.HexToAddress("0x55d398326f99059ff775485246999027b3197955"), Fee: 3000, TickSpacing: 60}
+
+	if registry.Observe(initializeLog(common.HexToAddress("0xbad"), key.ID(), key)) {
+		t.Error("Observe() accepted an Initialize event from another emitter")
+	}
+	forged := key
+	forged.Hooks = common.HexToAddress("0xbad")
+	if registry.Observe(initializeLog(manager, key.ID(), forged)) {
+		t.Error("Observe() accepted a key that does not hash to the PoolId")
+	}
+	if _, ok := registry.Lookup(key.ID()); ok {
+		t.Fatal("Lookup() found a rejected pool")
+	}
+
+	if !registry.Register(key.ID(), key) {
+		t.Fatal("Register() rejected a valid pool")
+	}
+	if registry.Register(key.ID(), key) {
+		t.Error("Register() overwrote a known pool")
+	}
+
+	if err := registry.LoadJSON(strings.NewReader(`{"` + key.ID().Hex() + `": {"fee": 500}}`)); err == nil {
+		t.Error("LoadJSON() accepted a key that does not hash to the PoolId")
+	}
+
+	// A bad entry rejects the whole file, valid entries included.
+	other := PoolKey{Currency1: common.HexToAddress("0x55d398326f99059ff775485246999027b3197955"), Fee: 500, TickSpacing: 10}
+	file := `{"` + other.ID().Hex() + `": {"currency1": "0x55d398326f99059ff775485246999027b3197955", "fee": 500, "tickSpacing": 10},` +
+		` "0x0000000000000000000000000000000000000000000000000000000000000001": {"fee": 500}}`
+	if err := registry.LoadJSON(strings.NewReader(file)); err == nil {
+		t.Error("LoadJSON() accepted a file with a key that does not hash to its PoolId")
+	}
+	if _, ok := registry.Lookup(other.ID()); ok {
+		t.Error("LoadJSON() registered entries of a rejected file")
+	}
+}
@@ -1,4 +1,4 @@
-// Package uniswapv4 provides swap event parsing for Uniswap V4 and compatible protocols.
+// Package uniswapv4 provides swap and liquidity event parsing for Uniswap V4 and compatible protocols.
 package uniswapv4
 
 import (
@@ -9,11 +9,23 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// V4Swap implements SwapEvent for Uniswap V4-style pools.
+// V4Swap implements SwapEvent for Uniswap V4-style pools. The amounts of the PoolManager
+// Swap event are balance deltas of the swapper: negative for the currency paid into the
+// pool, positive for the currency taken out.
 type V4Swap struct {
-	poolID  [32]byte // poolID is a 32-byte identifier for the pool, used as a unique pool identifier
+	poolID  [32]byte       // poolID is a 32-byte identifier for the pool, used as a unique pool identifier
+	manager common.Address // manager is the PoolManager that emitted the swap and holds the pool's currencies
 	amount0 *big.Int
 	amount1 *big.Int
+
+	// sqrtPriceX96, liquidity, tick and fee describe the pool after the swap; they are
+	// nil/zero for swaps settled entirely by a hook
+	sqrtPriceX96 *big.Int
+	liquidity    *big.Int
+	tick         int32
+	fee          uint32
+
+	hookSwaps []*HookSwap // hookSwaps are the hook deltas folded into amount0/amount1, if any
 }
 
 // PairID returns a pseudo-address derived from the first 20 bytes of poolID.
@@ -22,32 +34,69 @@ func (s *V4Swap) PairID() common.Address {
 	return common.BytesToAddress(s.poolID[:20])
 }
 
+// PoolID returns the full 32-byte PoolId of the pool.
+func (s *V4Swap) PoolID() common.Hash {
+	return s.poolID
+}
+
+// PoolManager returns the PoolManager that emitted the swap, which holds the currencies
+// of all its pools. It is the zero address for swaps settled entirely by a hook.
+func (s *V4Swap) PoolManager() common.Address {
+	return s.manager
+}
+
+// HookSwaps returns the hook-reported deltas combined into this swap, if any.
+func (s *V4Swap) HookSwaps() []*HookSwap {
+	return s.hookSwaps
+}
+
 // IsToken0To1 returns true if the swap direction is token0 -> token1.
 func (s *V4Swap) IsToken0To1() bool {
-	// amount0 > 0 means token0 is entering the pool (sell token0, buy token1)
-	return s.amount0.Sign() > 0
+	// The amounts are balance deltas of the swapper: amount0 < 0 means token0 was paid into the pool
+	return s.amount0.Sign() < 0
 }
 
 // AmountIn returns the input amount for the swap.
 func (s *V4Swap) AmountIn() *big.Int {
-	if s.amount0.Sign() > 0 {
-		return s.amount0
+	if s.IsToken0To1() {
+		return new(big.Int).Abs(s.amount0)
 	}
-	return new(big.Int).Neg(s.amount1)
+	return new(big.Int).Abs(s.amount1)
 }
 
 // AmountOut returns the output amount for the swap.
 func (s *V4Swap) AmountOut() *big.Int {
-	if s.amount0.Sign() < 0 {
-		return new(big.Int).Neg(s.amount0)
+	if s.IsToken0To1() {
+		return new(big.Int).Abs(s.amount1)
 	}
-	return s.amount1
+	return new(big.Int).Abs(s.amount0)
+}
+
+// SqrtPriceX96 returns the sqrt price of the pool after the swap, as a Q64.96.
+func (s *V4Swap) SqrtPriceX96() *big.Int {
+	return s.sqrtPriceX96
+}
+
+// Liquidity returns the in-range liquidity of the pool after the swap.
+func (s *V4Swap) Liquidity() *big.Int {
+	return s.liquidity
+}
+
+// Tick returns the tick of the pool after the swap.
+func (s *V4Swap) Tick() int32 {
+	return s.tick
+}
+
+// Fee returns the swap fee in hundredths of a bip.
+func (s *V4Swap) Fee() uint32 {
+	return s.fee
 }
 
 // ParseSwap parses a Uniswap V4 swap log into a V4Swap struct.
 // Returns nil if the log is not a valid swap event.
 func ParseSwap(log *types.Log) *V4Swap {
-	if len(log.Topics) != 3 || len(log.Data) < 64 {
+	// Data layout: amount0, amount1, sqrtPriceX96, liquidity, tick, fee
+	if len(log.Topics) != 3 || len(log.Data) < 192 {
 		return nil
 	}
 
@@ -58,8 +107,13 @@ func ParseSwap(log *types.Log) *V4Swap {
 	amount1 := tools.DecodeSignedInt256(log.Data[32:64])
 
 	return &V4Swap{
-		poolID:  poolID,
-		amount0: amount0,
-		amount1: amount1,
+		poolID:       poolID,
+		manager:      log.Address,
+		amount0:      amount0,
+		amount1:      amount1,
+		sqrtPriceX96: new(big.Int).SetBytes(log.Data[64:96]),
+		liquidity:    new(big.Int).SetBytes(log.Data[96:128]),
+		tick:         int32(tools.DecodeSignedInt256(log.Data[128:160]).Int64()),
+		fee:          uint32(new(big.Int).SetBytes(log.Data[160:192]).Uint64()),
 	}
 }
@@ -0,0 +1,28 @@
+package uniswapv4
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	poolID := common.HexToHash("0xc012e144f83cd4c616704b5391205ad0dc19719ca9f89b739a67a9b1d5316f17")
+	sender := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+
+	// The swapper pays 1000 token0 into the pool and takes 990 token1 out.
+	swap := ParseSwap(logtest.Log(common.HexToAddress("0x28e2Ea090877bF75740558f6BFB36A5ffeE9e9dF"),
+		"Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24)",
+		[]common.Hash{poolID, logtest.AddressTopic(sender)},
+		logtest.Word(-1000), logtest.Word(990), logtest.Word(1<<40), logtest.Word(5000), logtest.Word(-5), logtest.Word(3000)))
+	if swap == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+	if !swap.IsToken0To1() || swap.AmountIn().Int64() != 1000 || swap.AmountOut().Int64() != 990 {
+		t.Errorf("ParseSwap() = 0 to 1 %v, %v -> %v, want token0 -> token1, 1000 -> 990", swap.IsToken0To1(), swap.AmountIn(), swap.AmountOut())
+	}
+	if swap.PoolID() != poolID || swap.Tick() != -5 || swap.Fee() != 3000 {
+		t.Errorf("ParseSwap() pool = %s, tick = %d, fee = %d", swap.PoolID().Hex(), swap.Tick(), swap.Fee())
+	}
+}
@@ -0,0 +1,62 @@
+// Package venue provides the swap type shared by venues holding several assets in one
+// contract, whose swap events name the tokens traded.
+package venue
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Swap implements SwapEvent for a trade between two assets of a multi-asset venue.
+// Each token pair of the venue is treated as its own virtual pool.
+type Swap struct {
+	venue      common.Address
+	fromToken  common.Address
+	toToken    common.Address
+	fromAmount *big.Int
+	toAmount   *big.Int
+}
+
+// NewSwap returns the swap of fromAmount of fromToken for toAmount of toToken on the venue contract.
+func NewSwap(venue, fromToken, toToken common.Address, fromAmount, toAmount *big.Int) Swap {
+	return Swap{
+		venue:      venue,
+		fromToken:  fromToken,
+		toToken:    toToken,
+		fromAmount: fromAmount,
+		toAmount:   toAmount,
+	}
+}
+
+// PairID returns a pseudo-address derived from the venue contract and the sorted token pair.
+func (s *Swap) PairID() common.Address {
+	return tools.VirtualPairID(s.venue, s.fromToken, s.toToken)
+}
+
+// IsToken0To1 returns true if the swap direction is token0 -> token1, where token0 is
+// the lower token address.
+func (s *Swap) IsToken0To1() bool {
+	return tools.IsSortedPair(s.fromToken, s.toToken)
+}
+
+// AmountIn returns the input amount for the swap.
+func (s *Swap) AmountIn() *big.Int {
+	return new(big.Int).Set(s.fromAmount)
+}
+
+// AmountOut returns the output amount for the swap.
+func (s *Swap) AmountOut() *big.Int {
+	return new(big.Int).Set(s.toAmount)
+}
+
+// FromToken returns the token sold into the venue.
+func (s *Swap) FromToken() common.Address {
+	return s.fromToken
+}
+
+// ToToken returns the token bought from the venue.
+func (s *Swap) ToToken() common.Address {
+	return s.toToken
+}
@@ -0,0 +1,27 @@
+package venue
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSwap(t *testing.T) {
+	pool := common.HexToAddress("0x312Bc7eAAF93f1C60Dc5AfC115FcCDE161055fb0")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+
+	sell := NewSwap(pool, usdt, tools.WBNB, big.NewInt(3000), big.NewInt(10))
+	if sell.PairID() != tools.VirtualPairID(pool, usdt, tools.WBNB) || !sell.IsToken0To1() {
+		t.Errorf("Swap = pair %s, 0 to 1 %v, want the USDT/WBNB pair, token0 -> token1", sell.PairID().Hex(), sell.IsToken0To1())
+	}
+	if sell.AmountIn().Int64() != 3000 || sell.AmountOut().Int64() != 10 {
+		t.Errorf("Swap amounts = %v -> %v, want 3000 -> 10", sell.AmountIn(), sell.AmountOut())
+	}
+
+	buy := NewSwap(pool, tools.WBNB, usdt, big.NewInt(10), big.NewInt(2990))
+	if buy.PairID() != sell.PairID() || buy.IsToken0To1() {
+		t.Errorf("Swap reverse = pair %s, 0 to 1 %v, want the same pair, token1 -> token0", buy.PairID().Hex(), buy.IsToken0To1())
+	}
+}
@@ -0,0 +1,86 @@
+package protocols
+
+import (
+	"github.com/48Club/bscexorcist/protocols/poolverify"
+	"github.com/48Club/bscexorcist/protocols/transferflow"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// VerifyPools checks the emitters of V2/V3 swap events in the logs of a single transaction
+// against the verifier, taking the pool tokens from the ERC20 transfers the emitter sent or
+// received in the transaction. An emitter that no known factory derives is unverified if it
+// received one token and sent another, as every genuine swap does, and spoofed otherwise.
+// Transfers of the emitter's own token are not counted, so a token logging fake swaps and
+// transfers of itself is spoofed.
+func VerifyPools(logs []*types.Log, verifier *poolverify.Verifier) (spoofed, unverified []common.Address) {
+	tokens := make(map[common.Address][]common.Address)
+	received := make(map[common.Address]map[common.Address]bool)
+	sent := make(map[common.Address]map[common.Address]bool)
+	seen := make(map[[2]common.Address]bool)
+	for _, log := range logs {
+		transfer := transferflow.ParseTransfer(log)
+		if transfer == nil {
+			continue
+		}
+		addFlow(received, transfer.To, transfer.Token)
+		addFlow(sent, transfer.From, transfer.Token)
+		for _, account := range []common.Address{transfer.From, transfer.To} {
+			key := [2]common.Address{account, transfer.Token}
+			if !seen[key] {
+				seen[key] = true
+				tokens[account] = append(tokens[account], transfer.Token)
+			}
+		}
+	}
+
+	checked := make(map[common.Address]bool)
+	for _, log := range logs {
+		if len(log.Topics) == 0 || checked[log.Address] {
+			continue
+		}
+
+		var kind poolverify.Kind
+		if uniswapV2SwapSignatures[log.Topics[0]] {
+			kind = poolverify.KindV2
+		} else if uniswapV3SwapSignatures[log.Topics[0]] {
+			kind = poolverify.KindV3
+		} else {
+			continue
+		}
+
+		checked[log.Address] = true
+		if verifier.Verify(kind, log.Address, tokens[log.Address]) {
+			continue
+		}
+		if tradedTokens(received[log.Address], sent[log.Address]) {
+			unverified = append(unverified, log.Address)
+		} else {
+			spoofed = append(spoofed, log.Address)
+		}
+	}
+	return spoofed, unverified
+}
+
+// addFlow records that account received or sent token, unless the account is the token itself.
+func addFlow(flows map[common.Address]map[common.Address]bool, account, token common.Address) {
+	if account == token {
+		return
+	}
+	if flows[account] == nil {
+		flows[account] = make(map[common.Address]bool)
+	}
+	flows[account][token] = true
+}
+
+// tradedTokens returns true if a token was received and another one sent.
+func tradedTokens(received, sent map[common.Address]bool) bool {
+	for tokenIn := range received {
+		for tokenOut := range sent {
+			if tokenIn != tokenOut {
+				return true
+			}
+		}
+	}
+	return false
+}
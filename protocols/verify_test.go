@@ -0,0 +1,45 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/poolverify"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestVerifyPools(t *testing.T) {
+	var (
+		genuine = common.HexToAddress("0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE")
+		fork    = common.HexToAddress("0x00000000000000000000000000000000000f0f0f")
+		fake    = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		usdt    = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+		wbnb    = common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+		trader  = common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	)
+	var logs []*types.Log
+	for _, pool := range []common.Address{genuine, fork} {
+		logs = append(logs,
+			logtest.Transfer(usdt, trader, pool, 1000),
+			logtest.Transfer(wbnb, pool, trader, 1),
+			logtest.V2Swap(pool, trader, 1000, 0, 0, 1))
+	}
+	logs = append(logs, logtest.V2Swap(fake, trader, 1000, 0, 0, 1))
+
+	// A token faking swaps moves only itself, or a single other token.
+	token := common.HexToAddress("0x00000000000000000000000000000000000070c0")
+	logs = append(logs,
+		logtest.Transfer(token, trader, token, 1000),
+		logtest.Transfer(token, token, trader, 990),
+		logtest.Transfer(usdt, token, trader, 10),
+		logtest.V2Swap(token, trader, 1000, 0, 0, 990))
+
+	spoofed, unverified := VerifyPools(logs, poolverify.NewVerifier(poolverify.DefaultFactories))
+	if len(spoofed) != 2 || spoofed[0] != fake || spoofed[1] != token {
+		t.Errorf("VerifyPools() spoofed = %v, want %s and %s", spoofed, fake.Hex(), token.Hex())
+	}
+	if len(unverified) != 1 || unverified[0] != fork {
+		t.Errorf("VerifyPools() unverified = %v, want %s", unverified, fork.Hex())
+	}
+}
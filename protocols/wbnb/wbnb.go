@@ -0,0 +1,54 @@
+// Package wbnb provides event parsing for WBNB Deposit and Withdrawal events.
+package wbnb
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	depositSignature    = common.HexToHash("0xe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c")
+	withdrawalSignature = common.HexToHash("0x7fcf532c15f0a6db0bd6d0e038bea71d30d808c7d98cb3bf7268a95bf5081b65")
+)
+
+// Wrap represents a WBNB Deposit (native BNB wrapped) or Withdrawal (WBNB unwrapped).
+type Wrap struct {
+	Account common.Address
+	Amount  *big.Int
+	Deposit bool
+}
+
+// Delta returns the change of the account's WBNB balance.
+func (w *Wrap) Delta() *big.Int {
+	if w.Deposit {
+		return new(big.Int).Set(w.Amount)
+	}
+	return new(big.Int).Neg(w.Amount)
+}
+
+// ParseWrap parses a Deposit or Withdrawal log emitted by the given wrapped native token
+// (tools.WBNB on BSC) into a Wrap struct.
+// Returns nil if the log is not a valid wrap event.
+func ParseWrap(log *types.Log, wrappedNative common.Address) *Wrap {
+	if log.Address != wrappedNative || len(log.Topics) != 2 || len(log.Data) < 32 {
+		return nil
+	}
+
+	var deposit bool
+	switch log.Topics[0] {
+	case depositSignature:
+		deposit = true
+	case withdrawalSignature:
+		deposit = false
+	default:
+		return nil
+	}
+
+	return &Wrap{
+		Account: common.BytesToAddress(log.Topics[1].Bytes()),
+		Amount:  new(big.Int).SetBytes(log.Data[:32]),
+		Deposit: deposit,
+	}
+}
@@ -0,0 +1,29 @@
+package wbnb
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseWrap(t *testing.T) {
+	account := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	indexed := []common.Hash{logtest.AddressTopic(account)}
+
+	deposit := ParseWrap(logtest.Log(tools.WBNB, "Deposit(address,uint256)", indexed, logtest.Word(100)), tools.WBNB)
+	if deposit == nil || !deposit.Deposit || deposit.Account != account || deposit.Delta().Int64() != 100 {
+		t.Errorf("ParseWrap() deposit = %+v", deposit)
+	}
+	withdrawal := ParseWrap(logtest.Log(tools.WBNB, "Withdrawal(address,uint256)", indexed, logtest.Word(100)), tools.WBNB)
+	if withdrawal == nil || withdrawal.Deposit || withdrawal.Delta().Int64() != -100 {
+		t.Errorf("ParseWrap() withdrawal = %+v", withdrawal)
+	}
+
+	// Any contract can emit a Deposit event.
+	fake := logtest.Log(common.HexToAddress("0x00000000000000000000000000000000deadbeef"), "Deposit(address,uint256)", indexed, logtest.Word(100))
+	if wrap := ParseWrap(fake, tools.WBNB); wrap != nil {
+		t.Errorf("ParseWrap() from another contract = %+v, want nil", wrap)
+	}
+}
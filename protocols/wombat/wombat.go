@@ -0,0 +1,35 @@
+// Package wombat provides swap event parsing for Wombat pools.
+package wombat
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/venue"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// WombatSwap implements SwapEvent for Wombat pools. A Wombat pool is a stableswap
+// over several assets sharing one invariant, so the event names the assets traded
+// and every pair of them is a virtual pool of the contract.
+type WombatSwap struct {
+	venue.Swap
+}
+
+// ParseSwap parses a Wombat swap log into a WombatSwap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseSwap(log *types.Log) *WombatSwap {
+	// Topics: signature, sender, to
+	// Data layout: fromToken, toToken, fromAmount, toAmount
+	if len(log.Topics) != 3 || len(log.Data) < 128 {
+		return nil
+	}
+
+	return &WombatSwap{venue.NewSwap(
+		log.Address,
+		common.BytesToAddress(log.Data[:32]),
+		common.BytesToAddress(log.Data[32:64]),
+		new(big.Int).SetBytes(log.Data[64:96]),
+		new(big.Int).SetBytes(log.Data[96:128]),
+	)}
+}
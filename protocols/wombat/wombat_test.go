@@ -0,0 +1,39 @@
+package wombat
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	pool := common.HexToAddress("0x312Bc7eAAF93f1C60Dc5AfC115FcCDE161055fb0")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	usdc := common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d")
+	busd := common.HexToAddress("0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	// Topics: sender, to. Data layout: fromToken, toToken, fromAmount, toAmount
+	swapLog := func(fromToken, toToken common.Address, fromAmount, toAmount int64) *WombatSwap {
+		return ParseSwap(logtest.Log(pool, "Swap(address,address,address,uint256,uint256,address)",
+			[]common.Hash{logtest.AddressTopic(trader), logtest.AddressTopic(trader)},
+			logtest.AddressWord(fromToken), logtest.AddressWord(toToken), logtest.Word(fromAmount), logtest.Word(toAmount)))
+	}
+
+	swap := swapLog(usdc, usdt, 1000, 999)
+	if swap == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+	if swap.PairID() != tools.VirtualPairID(pool, usdt, usdc) || swap.IsToken0To1() {
+		t.Errorf("ParseSwap() = pair %s, 0 to 1 %v, want the USDT/USDC pair, token1 -> token0", swap.PairID().Hex(), swap.IsToken0To1())
+	}
+	if swap.FromToken() != usdc || swap.ToToken() != usdt || swap.AmountIn().Int64() != 1000 || swap.AmountOut().Int64() != 999 {
+		t.Errorf("ParseSwap() = %s -> %s, %v -> %v", swap.FromToken().Hex(), swap.ToToken().Hex(), swap.AmountIn(), swap.AmountOut())
+	}
+
+	// Each pair of assets of the pool is a separate virtual pool.
+	if other := swapLog(usdt, busd, 1000, 999); other.PairID() == swap.PairID() {
+		t.Error("ParseSwap() pairs of one pool share a PairID")
+	}
+}
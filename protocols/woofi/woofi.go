@@ -0,0 +1,35 @@
+// Package woofi provides swap event parsing for WOOFi WooPPV2.
+package woofi
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/venue"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// WooSwap implements SwapEvent for WooPPV2. WooPPV2 is a single contract holding
+// every token and pricing each one against its oracle, so the event names both
+// tokens and every pair of them is a virtual pool of the contract.
+type WooSwap struct {
+	venue.Swap
+}
+
+// ParseSwap parses a WooSwap log into a WooSwap struct.
+// Returns nil if the log is not a valid swap event.
+func ParseSwap(log *types.Log) *WooSwap {
+	// Topics: signature, fromToken, toToken, to
+	// Data layout: fromAmount, toAmount, from, rebateTo, swapVol, swapFee
+	if len(log.Topics) != 4 || len(log.Data) < 192 {
+		return nil
+	}
+
+	return &WooSwap{venue.NewSwap(
+		log.Address,
+		common.BytesToAddress(log.Topics[1].Bytes()),
+		common.BytesToAddress(log.Topics[2].Bytes()),
+		new(big.Int).SetBytes(log.Data[:32]),
+		new(big.Int).SetBytes(log.Data[32:64]),
+	)}
+}
@@ -0,0 +1,38 @@
+package woofi
+
+import (
+	"testing"
+
+	"github.com/48Club/bscexorcist/internal/logtest"
+	"github.com/48Club/bscexorcist/protocols/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSwap(t *testing.T) {
+	wooPP := common.HexToAddress("0x59dE3B49314Bf5067719364A00E6a4e0aaa3d9Ac")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	trader := common.HexToAddress("0x2104ba06a100d8cb1a3985237842d5647c157ab8")
+	// Topics: fromToken, toToken, to. Data layout: fromAmount, toAmount, from, rebateTo, swapVol, swapFee
+	swapLog := func(fromToken, toToken common.Address, fromAmount, toAmount int64) *WooSwap {
+		return ParseSwap(logtest.Log(wooPP, "WooSwap(address,address,uint256,uint256,address,address,address,uint256,uint256)",
+			[]common.Hash{logtest.AddressTopic(fromToken), logtest.AddressTopic(toToken), logtest.AddressTopic(trader)},
+			logtest.Word(fromAmount), logtest.Word(toAmount), logtest.AddressWord(trader), logtest.AddressWord(common.Address{}),
+			logtest.Word(fromAmount), logtest.Word(1)))
+	}
+
+	sell := swapLog(usdt, tools.WBNB, 3000, 10)
+	if sell == nil {
+		t.Fatal("ParseSwap() = nil")
+	}
+	if sell.PairID() != tools.VirtualPairID(wooPP, usdt, tools.WBNB) || !sell.IsToken0To1() {
+		t.Errorf("ParseSwap() = pair %s, 0 to 1 %v, want the USDT/WBNB pair, token0 -> token1", sell.PairID().Hex(), sell.IsToken0To1())
+	}
+	if sell.AmountIn().Int64() != 3000 || sell.AmountOut().Int64() != 10 {
+		t.Errorf("ParseSwap() amounts = %v -> %v, want 3000 -> 10", sell.AmountIn(), sell.AmountOut())
+	}
+
+	buy := swapLog(tools.WBNB, usdt, 10, 2990)
+	if buy.PairID() != sell.PairID() || buy.IsToken0To1() {
+		t.Errorf("ParseSwap() reverse = pair %s, 0 to 1 %v, want the same pair, token1 -> token0", buy.PairID().Hex(), buy.IsToken0To1())
+	}
+}
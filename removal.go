@@ -0,0 +1,121 @@
+package bscexorcist
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols"
+	"github.com/48Club/bscexorcist/protocols/uniswapv2"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LiquidityRemovalError is returned when a provider withdraws liquidity from a position right
+// before a victim swap and deposits it again right after, deepening the victim's slippage.
+// Transactions are identified by their index in the bundle.
+type LiquidityRemovalError struct {
+	Pool common.Address
+	// Provider is the owner of the position, the NFT holder for positions held through a
+	// position manager whose NFT was minted in the bundle.
+	Provider common.Address
+	RemoveTx int
+	VictimTx int
+	AddTx    int
+	// ExtraSlippage is how much less of the output token the victim received than it would
+	// have with the withdrawn liquidity still in the pool, before fees. It is estimated from
+	// V2 reserves and nil if they are unknown.
+	ExtraSlippage *big.Int
+	// Score weighs the evidence that the pattern is an attack.
+	Score *Score
+}
+
+// Error implements the error interface.
+func (e *LiquidityRemovalError) Error() string {
+	msg := fmt.Sprintf("liquidity removal sandwich detected on pool: %s by provider %s", e.Pool.Hex(), e.Provider.Hex())
+	if e.ExtraSlippage != nil {
+		msg += fmt.Sprintf(" (extra slippage: %s)", e.ExtraSlippage)
+	}
+	if e.Score != nil {
+		msg += fmt.Sprintf("; verdict %s (score %g)", e.Score.Verdict, e.Score.Total)
+	}
+	return msg
+}
+
+// findLiquidityRemoval returns the first withdrawal that was followed by a swap of another
+// transaction on the same pool and then a deposit by the same owner to the same position.
+// V2 liquidity is fungible, so V2 withdrawals and deposits are matched by owner alone.
+func findLiquidityRemoval(pools []common.Address, poolLegs map[common.Address][]poolLeg, liquidityLegs map[common.Address][]liquidityLeg, reserves *uniswapv2.PoolState) *LiquidityRemovalError {
+	for _, pool := range pools {
+		legs := liquidityLegs[pool]
+		for i, remove := range legs {
+			if remove.event.IsAdd() {
+				continue
+			}
+			for _, add := range legs[i+1:] {
+				if !add.event.IsAdd() || add.txIndex <= remove.txIndex+1 || !sameLiquidityOwner(add.event, remove.event) {
+					continue
+				}
+				for _, swap := range poolLegs[pool] {
+					if swap.txIndex <= remove.txIndex || swap.txIndex >= add.txIndex {
+						continue
+					}
+					return &LiquidityRemovalError{
+						Pool:          pool,
+						Provider:      protocols.LiquidityOwner(remove.event),
+						RemoveTx:      remove.txIndex,
+						VictimTx:      swap.txIndex,
+						AddTx:         add.txIndex,
+						ExtraSlippage: extraSlippage(reserves, pool, swap.txIndex, remove),
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sameLiquidityOwner returns true if a and b changed the same position of the same owner.
+// V3 and V4 position ids already commit to the owner, or to the NFT of positions held
+// through a position manager; V2 liquidity is one fungible position, matched by owner.
+func sameLiquidityOwner(a, b protocols.LiquidityEvent) bool {
+	if a.PositionID() != b.PositionID() {
+		return false
+	}
+	return a.PositionID() != (common.Hash{}) || protocols.LiquidityOwner(a) == protocols.LiquidityOwner(b)
+}
+
+// withdrewPosition returns true if the transaction txIndex withdrew from a V3 or V4 position
+// rather than fungible V2 liquidity.
+func withdrewPosition(legs []liquidityLeg, txIndex int) bool {
+	for _, leg := range legs {
+		if leg.txIndex == txIndex && !leg.event.IsAdd() && leg.event.PositionID() != (common.Hash{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// extraSlippage compares the victim's V2 swap output against the output it would have had
+// with the withdrawn amounts still in the pool. Returns nil if reserves or amounts are unknown.
+func extraSlippage(reserves *uniswapv2.PoolState, pool common.Address, victimTx int, remove liquidityLeg) *big.Int {
+	snapshots := reserves.TxSnapshots(pool, victimTx)
+	removed0, removed1 := remove.event.Amount0(), remove.event.Amount1()
+	if len(snapshots) == 0 || removed0 == nil || removed1 == nil {
+		return nil
+	}
+
+	snapshot := snapshots[0]
+	reserveIn, reserveOut := snapshot.Reserve0Before, snapshot.Reserve1Before
+	removedIn, removedOut := removed0, removed1
+	if !snapshot.Swap.IsToken0To1() {
+		reserveIn, reserveOut = reserveOut, reserveIn
+		removedIn, removedOut = removedOut, removedIn
+	}
+
+	amountIn := snapshot.Swap.AmountIn()
+	actual := uniswapv2.QuoteOut(amountIn, reserveIn, reserveOut)
+	counterfactual := uniswapv2.QuoteOut(amountIn, new(big.Int).Add(reserveIn, removedIn), new(big.Int).Add(reserveOut, removedOut))
+	if actual == nil || counterfactual == nil {
+		return nil
+	}
+	return counterfactual.Sub(counterfactual, actual)
+}
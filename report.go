@@ -0,0 +1,132 @@
+package bscexorcist
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols"
+	"github.com/48Club/bscexorcist/protocols/aggregator"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Report is the outcome of analyzing a bundle with Detector.Analyze.
+type Report struct {
+	// Sandwich is the sandwich detected in the bundle, nil if none was found.
+	Sandwich *SandwichError
+	// JIT is the just-in-time liquidity attack detected in the bundle, nil if none was found.
+	JIT *JITLiquidityError
+	// LiquidityRemoval is the liquidity-removal sandwich detected in the bundle, nil if none was found.
+	LiquidityRemoval *LiquidityRemovalError
+	// AddLiquidity is the sandwich of a liquidity deposit detected in the bundle, nil if none was found.
+	AddLiquidity *AddLiquiditySandwichError
+	// SpoofedPools lists emitters of V2/V3 swap events that failed pool verification and
+	// moved no tokens like a pool. Their swaps were ignored by detection.
+	SpoofedPools []FlaggedPool
+	// UnverifiedPools lists emitters of V2/V3 swap events that failed pool verification but
+	// traded tokens like a pool, e.g. pools of unlisted forks. Their swaps were ignored by
+	// detection unless Options.KeepUnverifiedPools is set.
+	UnverifiedPools []FlaggedPool
+	// Classes classifies each transaction of the bundle by the shape of its swaps.
+	// Classes is nil for bundles of fewer than 3 transactions, which are not analyzed.
+	Classes []protocols.TxClass
+	// Verdict is the overall assessment of the bundle, the highest verdict of the Scores
+	// of the attacks detected.
+	Verdict Verdict
+}
+
+// raiseVerdict raises the report verdict to the verdict of score if it is higher.
+func (r *Report) raiseVerdict(score *Score) {
+	if score.Verdict > r.Verdict {
+		r.Verdict = score.Verdict
+	}
+}
+
+// Err returns the detected attack as an error, or nil if the bundle is clean.
+// A swap sandwich takes precedence over liquidity attacks. Attacks scoring below the
+// Suspicious threshold are ignored.
+func (r *Report) Err() error {
+	if r.Sandwich != nil && r.Sandwich.Score.Verdict != VerdictClean {
+		return r.Sandwich
+	}
+	if r.JIT != nil && r.JIT.Score.Verdict != VerdictClean {
+		return r.JIT
+	}
+	if r.LiquidityRemoval != nil && r.LiquidityRemoval.Score.Verdict != VerdictClean {
+		return r.LiquidityRemoval
+	}
+	if r.AddLiquidity != nil && r.AddLiquidity.Score.Verdict != VerdictClean {
+		return r.AddLiquidity
+	}
+	return nil
+}
+
+// FlaggedPool is a swap event emitter that is not a pool of a known factory, with the
+// transaction it was seen in.
+type FlaggedPool struct {
+	TxIndex int
+	Pool    common.Address
+}
+
+// SandwichError is returned when a sandwich pattern is detected on a pool.
+// Transactions are identified by their index in the bundle.
+type SandwichError struct {
+	Pool     common.Address
+	FrontTx  int
+	VictimTx int
+	BackTx   int
+	// Victims lists every transaction that traded the pool in the front-run's direction
+	// between the front-run and the back-run. VictimTx is the first of them.
+	Victims []Victim
+	// VictimRoute is the Route of the first victim.
+	VictimRoute *aggregator.Route
+	// Price compares the pool prices around the legs; nil unless the pool's reserves
+	// are known from V2 Sync events.
+	Price *PriceEvidence
+	// Heuristic is true if the pattern involves swaps inferred from token transfers.
+	Heuristic bool
+	// Score weighs the evidence that the pattern is a sandwich.
+	Score *Score
+}
+
+// Victim is a transaction caught between the legs of a sandwich, with its trade on the pool.
+type Victim struct {
+	TxIndex   int
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	// Route is the victim's end-to-end trade if it went through an aggregator router that
+	// traded on the sandwiched pool, as found by protocols.RouteForPool.
+	Route *aggregator.Route
+}
+
+// PriceEvidence compares V2 pool prices around the front-run and the victim.
+type PriceEvidence struct {
+	// FrontRunImpact is the relative spot price move caused by the front-run.
+	FrontRunImpact *big.Float
+	// VictimPriceImpact is the relative spot price move caused by the victim.
+	VictimPriceImpact *big.Float
+	// MovedAgainstVictim is true if the victim traded at a worse spot price than the
+	// pool had before the front-run.
+	MovedAgainstVictim bool
+}
+
+// Error implements the error interface.
+func (e *SandwichError) Error() string {
+	msg := fmt.Sprintf("sandwich attack detected on pool: %s", e.Pool.Hex())
+	if e.Heuristic {
+		msg += " (heuristic: swaps inferred from token transfers)"
+	}
+	if route := e.VictimRoute; route != nil {
+		msg += fmt.Sprintf("; victim tx %d traded %s %s for %s %s via router %s",
+			e.VictimTx, route.SpentAmount, route.SrcToken.Hex(), route.ReturnAmount, route.DstToken.Hex(), route.Router.Hex())
+		if rate := route.RealizedRate(); rate != nil {
+			msg += fmt.Sprintf(" (rate %s)", rate.Text('g', 6))
+		}
+	}
+	if len(e.Victims) > 1 {
+		msg += fmt.Sprintf("; %d victims", len(e.Victims))
+	}
+	if e.Score != nil {
+		msg += fmt.Sprintf("; verdict %s (score %g)", e.Score.Verdict, e.Score.Total)
+	}
+	return msg
+}
@@ -0,0 +1,200 @@
+package bscexorcist
+
+import (
+	"math/big"
+
+	"github.com/48Club/bscexorcist/protocols/transferflow"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Verdict is the overall assessment of a bundle.
+type Verdict int
+
+const (
+	// VerdictClean means no attack pattern was found.
+	VerdictClean Verdict = iota
+	// VerdictSuspicious means a pattern was found but the evidence is not conclusive.
+	VerdictSuspicious
+	// VerdictSandwich means the evidence is conclusive.
+	VerdictSandwich
+)
+
+// String returns the verdict name.
+func (v Verdict) String() string {
+	switch v {
+	case VerdictClean:
+		return "clean"
+	case VerdictSuspicious:
+		return "suspicious"
+	case VerdictSandwich:
+		return "sandwich"
+	}
+	return "unknown"
+}
+
+// Default verdict thresholds, used when the Options thresholds are zero.
+const (
+	DefaultSuspiciousScore = 1
+	DefaultSandwichScore   = 4
+)
+
+// Signal weights. The ordering pattern alone reaches DefaultSuspiciousScore.
+const (
+	weightPattern          = 1
+	weightSharedIdentity   = 2
+	weightAmountMatch      = 2
+	weightProfit           = 2
+	weightPriceImpact      = 1
+	weightKnownBot         = 3
+	weightBundlePosition   = 1
+	weightSamePosition     = 2
+	amountMatchToleranceBP = 500 // back-run input within 5% of the front-run output
+)
+
+// Signal is a piece of evidence that contributed to a Score.
+type Signal struct {
+	Name   string
+	Weight float64
+}
+
+// Score is the weighted evidence that a detected pattern is a sandwich.
+type Score struct {
+	Total   float64
+	Signals []Signal
+	Verdict Verdict
+}
+
+func (s *Score) add(name string, weight float64) {
+	s.Signals = append(s.Signals, Signal{Name: name, Weight: weight})
+	s.Total += weight
+}
+
+// thresholds returns the verdict thresholds, falling back to the defaults.
+func (o Options) thresholds() (suspicious, sandwich float64) {
+	suspicious, sandwich = o.SuspiciousScore, o.SandwichScore
+	if suspicious == 0 {
+		suspicious = DefaultSuspiciousScore
+	}
+	if sandwich == 0 {
+		sandwich = DefaultSandwichScore
+	}
+	return suspicious, sandwich
+}
+
+// scoreSandwich combines the independent signals of a detected sandwich into a Score.
+func (d *Detector) scoreSandwich(bundleLogs [][]*types.Log, sandwichErr *SandwichError, legs []poolLeg, pools []common.Address) *Score {
+	score := &Score{}
+	score.add("ordering pattern", weightPattern)
+
+	var front, back poolLeg
+	for _, leg := range legs {
+		if leg.txIndex == sandwichErr.FrontTx {
+			front = leg
+		} else if leg.txIndex == sandwichErr.BackTx {
+			back = leg
+		}
+	}
+
+	victimTxs := make([]int, len(sandwichErr.Victims))
+	for i, victim := range sandwichErr.Victims {
+		victimTxs[i] = victim.TxIndex
+	}
+	if sharedIdentity(bundleLogs, pools, front.txIndex, back.txIndex, victimTxs...) {
+		score.add("shared attacker identity", weightSharedIdentity)
+	}
+
+	// The back-run sells what the front-run bought.
+	bought, sold := front.swap.AmountOut(), back.swap.AmountIn()
+	diff := new(big.Int).Sub(bought, sold)
+	diff.Abs(diff).Mul(diff, big.NewInt(10000))
+	if bought.Sign() > 0 && diff.Cmp(new(big.Int).Mul(bought, big.NewInt(amountMatchToleranceBP))) <= 0 {
+		score.add("front/back amount match", weightAmountMatch)
+	}
+
+	if back.swap.AmountOut().Cmp(front.swap.AmountIn()) > 0 {
+		score.add("attacker profit", weightProfit)
+	}
+
+	if sandwichErr.Price != nil && sandwichErr.Price.MovedAgainstVictim {
+		score.add("victim price impact", weightPriceImpact)
+	}
+
+	d.scoreAttackers(score, bundleLogs, front.txIndex, back.txIndex)
+	return score
+}
+
+// scoreLiquidity combines the signals of a liquidity attack whose attacker transactions
+// firstTx and lastTx surround victimTx into a Score. samePosition is true if both
+// attacker transactions changed the same liquidity position.
+func (d *Detector) scoreLiquidity(bundleLogs [][]*types.Log, pools []common.Address, firstTx, victimTx, lastTx int, samePosition bool) *Score {
+	score := &Score{}
+	score.add("ordering pattern", weightPattern)
+	if samePosition {
+		score.add("same position", weightSamePosition)
+	}
+	if sharedIdentity(bundleLogs, pools, firstTx, lastTx, victimTx) {
+		score.add("shared attacker identity", weightSharedIdentity)
+	}
+	d.scoreAttackers(score, bundleLogs, firstTx, lastTx)
+	return score
+}
+
+// scoreAttackers adds the known bot and bundle position signals of the attacker
+// transactions firstTx and lastTx and sets the verdict.
+func (d *Detector) scoreAttackers(score *Score, bundleLogs [][]*types.Log, firstTx, lastTx int) {
+	firstParties, lastParties := transferParties(bundleLogs[firstTx]), transferParties(bundleLogs[lastTx])
+	for _, bot := range d.opts.KnownBots {
+		if firstParties[bot] || lastParties[bot] {
+			score.add("known bot", weightKnownBot)
+			break
+		}
+	}
+
+	if firstTx == 0 && lastTx == len(bundleLogs)-1 {
+		score.add("bundle position", weightBundlePosition)
+	}
+
+	suspicious, sandwich := d.opts.thresholds()
+	switch {
+	case score.Total >= sandwich:
+		score.Verdict = VerdictSandwich
+	case score.Total >= suspicious:
+		score.Verdict = VerdictSuspicious
+	}
+}
+
+// sharedIdentity returns true if an account other than the pools and the parties of the
+// victim transactions sent or received tokens in both attacker transactions.
+func sharedIdentity(bundleLogs [][]*types.Log, pools []common.Address, firstTx, lastTx int, victimTxs ...int) bool {
+	excluded := make(map[common.Address]bool)
+	for _, pool := range pools {
+		excluded[pool] = true
+	}
+	for _, victimTx := range victimTxs {
+		for account := range transferParties(bundleLogs[victimTx]) {
+			excluded[account] = true
+		}
+	}
+
+	lastParties := transferParties(bundleLogs[lastTx])
+	for account := range transferParties(bundleLogs[firstTx]) {
+		if lastParties[account] && !excluded[account] {
+			return true
+		}
+	}
+	return false
+}
+
+// transferParties returns the senders and recipients of the ERC20 transfers in a transaction's logs.
+func transferParties(logs []*types.Log) map[common.Address]bool {
+	parties := make(map[common.Address]bool)
+	for _, log := range logs {
+		if transfer := transferflow.ParseTransfer(log); transfer != nil {
+			parties[transfer.From] = true
+			parties[transfer.To] = true
+		}
+	}
+	delete(parties, common.Address{})
+	return parties
+}